@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	ExitSuccess         = 0
+	ExitFailure         = 1
+	ExitUsage           = 2
+	ExitPartialFailure  = 3
+	ExitNothingToDo     = 4
+	ExitUpdateAvailable = 5
+)
+
+type Outcome int
+
+const (
+	OutcomeChanged Outcome = iota
+	OutcomeUnchanged
+	OutcomePending
+	OutcomeFailed
+)
+
+type BatchRow struct {
+	Name    string
+	Client  string
+	Scope   string
+	Result  string
+	Outcome Outcome
+}
+
+type BatchSummary struct {
+	Changed   int
+	Unchanged int
+	Pending   int
+	Failed    int
+}
+
+func SummarizeBatch(rows []BatchRow) BatchSummary {
+	var summary BatchSummary
+	for _, row := range rows {
+		switch row.Outcome {
+		case OutcomeChanged:
+			summary.Changed++
+		case OutcomeUnchanged:
+			summary.Unchanged++
+		case OutcomePending:
+			summary.Pending++
+		case OutcomeFailed:
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+func (s BatchSummary) ExitCode() int {
+	switch {
+	case s.Failed > 0 && s.Changed+s.Unchanged+s.Pending == 0:
+		return ExitFailure
+	case s.Failed > 0:
+		return ExitPartialFailure
+	case s.Pending > 0:
+		return ExitUpdateAvailable
+	case s.Changed > 0:
+		return ExitSuccess
+	default:
+		return ExitNothingToDo
+	}
+}
+
+func (s BatchSummary) String() string {
+	var parts []string
+	if s.Changed > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", s.Changed))
+	}
+	if s.Pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", s.Pending))
+	}
+	if s.Unchanged > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", s.Unchanged))
+	}
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	if len(parts) == 0 {
+		return "nothing to do"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func PrintBatchTable(out io.Writer, rows []BatchRow) error {
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tCLIENT\tSCOPE\tRESULT")
+	for _, row := range rows {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", row.Name, row.Client, row.Scope, row.Result)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\nsummary: %s\n", SummarizeBatch(rows))
+	return err
+}
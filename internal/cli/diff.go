@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffOp struct {
+	kind byte
+	text string
+	a    int
+	b    int
+}
+
+func UnifiedDiff(fromName, toName string, from, to []byte) string {
+	a, aNoEOL := splitDiffLines(string(from))
+	b, bNoEOL := splitDiffLines(string(to))
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	for _, hunk := range diffHunks(ops) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		first := ops[hunk[0]]
+		aStart, bStart := first.a, first.b
+		aCount, bCount := 0, 0
+		for _, op := range ops[hunk[0]:hunk[1]] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, op := range ops[hunk[0]:hunk[1]] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.text)
+			out.WriteByte('\n')
+			if (op.kind != '+' && aNoEOL && op.a == len(a)-1) || (op.kind != '-' && bNoEOL && op.b == len(b)-1) {
+				if op.kind == ' ' && aNoEOL != bNoEOL {
+					continue
+				}
+				out.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+func splitDiffLines(text string) ([]string, bool) {
+	if text == "" {
+		return nil, false
+	}
+	noEOL := !strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n"), noEOL
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func diffHunks(ops []diffOp) [][2]int {
+	var hunks [][2]int
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+		}
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+		hunks = append(hunks, [2]int{start, stop})
+		i = stop
+	}
+	return hunks
+}
+
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: ' ', text: a[i], a: i, b: i})
+	}
+	x, y := prefix, prefix
+	for _, kind := range myersScript(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		switch kind {
+		case ' ':
+			ops = append(ops, diffOp{kind: ' ', text: a[x], a: x, b: y})
+			x++
+			y++
+		case '-':
+			ops = append(ops, diffOp{kind: '-', text: a[x], a: x, b: y})
+			x++
+		case '+':
+			ops = append(ops, diffOp{kind: '+', text: b[y], a: x, b: y})
+			y++
+		}
+	}
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, diffOp{kind: ' ', text: a[x+i], a: x + i, b: y + i})
+	}
+	return ops
+}
+
+func myersScript(a, b []string) []byte {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		script := make([]byte, 0, n+m)
+		for i := 0; i < n; i++ {
+			script = append(script, '-')
+		}
+		for i := 0; i < m; i++ {
+			script = append(script, '+')
+		}
+		return script
+	}
+
+	max := n + m
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	depth := 0
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				depth = d
+				break search
+			}
+		}
+	}
+
+	var script []byte
+	x, y := n, m
+	for d := depth; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && prev[offset+k-1] < prev[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := prev[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			script = append(script, ' ')
+			x--
+			y--
+		}
+		if x == prevX {
+			script = append(script, '+')
+			y--
+		} else {
+			script = append(script, '-')
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		script = append(script, ' ')
+		x--
+		y--
+	}
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
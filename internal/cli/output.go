@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const OutputSchemaVersion = 1
+
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputYAML OutputFormat = "yaml"
+)
+
+type outputDocument struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Kind          string `json:"kind"`
+	Items         any    `json:"items"`
+}
+
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "text":
+		return OutputText, nil
+	case "json":
+		return OutputJSON, nil
+	case "yaml", "yml":
+		return OutputYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s (use text, json or yaml)", value)
+	}
+}
+
+func (f OutputFormat) Structured() bool {
+	return f == OutputJSON || f == OutputYAML
+}
+
+func WriteOutput(out io.Writer, format OutputFormat, kind string, items any) error {
+	if items == nil {
+		items = []any{}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(outputDocument{SchemaVersion: OutputSchemaVersion, Kind: kind, Items: items}); err != nil {
+		return err
+	}
+	if format != OutputYAML {
+		_, err := out.Write(buf.Bytes())
+		return err
+	}
+
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return err
+	}
+	var yaml strings.Builder
+	writeYAMLValue(&yaml, value, 0, false)
+	_, err = io.WriteString(out, yaml.String())
+	return err
+}
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+type orderedObject []orderedField
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch delim := token.(type) {
+	case json.Delim:
+		switch delim {
+		case '{':
+			var object orderedObject
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				object = append(object, orderedField{key: keyToken.(string), value: value})
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return object, nil
+		case '[':
+			list := []any{}
+			for decoder.More() {
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+	}
+	return token, nil
+}
+
+func writeYAMLValue(out *strings.Builder, value any, indent int, inList bool) {
+	pad := strings.Repeat("  ", indent)
+	switch typed := value.(type) {
+	case orderedObject:
+		if len(typed) == 0 {
+			out.WriteString("{}\n")
+			return
+		}
+		for i, field := range typed {
+			if i > 0 || !inList {
+				out.WriteString(pad)
+			}
+			out.WriteString(yamlOutputScalar(field.key))
+			out.WriteString(":")
+			writeYAMLChild(out, field.value, indent)
+		}
+	case []any:
+		if len(typed) == 0 {
+			out.WriteString("[]\n")
+			return
+		}
+		for i, item := range typed {
+			if i > 0 || !inList {
+				out.WriteString(pad)
+			}
+			out.WriteString("- ")
+			if isYAMLCollection(item) {
+				writeYAMLValue(out, item, indent+1, true)
+				continue
+			}
+			out.WriteString(yamlOutputScalar(item))
+			out.WriteString("\n")
+		}
+	default:
+		out.WriteString(yamlOutputScalar(value))
+		out.WriteString("\n")
+	}
+}
+
+func writeYAMLChild(out *strings.Builder, value any, indent int) {
+	switch typed := value.(type) {
+	case orderedObject:
+		if len(typed) == 0 {
+			out.WriteString(" {}\n")
+			return
+		}
+		out.WriteString("\n")
+		writeYAMLValue(out, value, indent+1, false)
+	case []any:
+		if len(typed) == 0 {
+			out.WriteString(" []\n")
+			return
+		}
+		out.WriteString("\n")
+		writeYAMLValue(out, value, indent+1, false)
+	default:
+		out.WriteString(" ")
+		out.WriteString(yamlOutputScalar(value))
+		out.WriteString("\n")
+	}
+}
+
+func isYAMLCollection(value any) bool {
+	switch typed := value.(type) {
+	case orderedObject:
+		return len(typed) > 0
+	case []any:
+		return len(typed) > 0
+	}
+	return false
+}
+
+func yamlOutputScalar(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(typed)
+	case json.Number:
+		return typed.String()
+	case orderedObject:
+		return "{}"
+	case []any:
+		return "[]"
+	case string:
+		if yamlNeedsQuotes(typed) {
+			return strconv.Quote(typed)
+		}
+		return typed
+	}
+	return fmt.Sprint(value)
+}
+
+func yamlNeedsQuotes(value string) bool {
+	if value == "" || strings.TrimSpace(value) != value {
+		return true
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return true
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	if looksLikeYAMLDate(value) {
+		return true
+	}
+	if strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") {
+		return true
+	}
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+func looksLikeYAMLDate(value string) bool {
+	if len(value) < len("2006-1-2") || value[4] != '-' {
+		return false
+	}
+	for _, r := range value[:4] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"mcp-skill-manager/internal/plan"
+)
+
+func PrintPlan(out io.Writer, changes []plan.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "dry run: nothing would change")
+		return
+	}
+	fmt.Fprintf(out, "dry run: %d planned change(s), nothing was written\n", len(changes))
+	spaced := false
+	for _, change := range changes {
+		withDiff := !change.Dir && (change.Kind == plan.Create || change.Kind == plan.Modify)
+		if withDiff || spaced {
+			fmt.Fprintln(out)
+		}
+		spaced = withDiff
+		switch {
+		case change.Kind == plan.Run:
+			if change.Path != "" {
+				fmt.Fprintf(out, "run %s (in %s)\n", change.Command, change.Path)
+				continue
+			}
+			fmt.Fprintf(out, "run %s\n", change.Command)
+		case change.Dir && change.Kind == plan.Delete:
+			fmt.Fprintf(out, "delete %s/\n", change.Path)
+		case change.Dir && change.Kind == plan.Create:
+			fmt.Fprintf(out, "create %s/ (copy of %s)\n", change.Path, change.Source)
+		case change.Dir:
+			fmt.Fprintf(out, "replace %s/ (copy of %s)\n", change.Path, change.Source)
+		case change.Kind == plan.Delete:
+			fmt.Fprintf(out, "delete %s\n", change.Path)
+		case change.Kind == plan.Create:
+			fmt.Fprintf(out, "create %s\n", change.Path)
+			fmt.Fprint(out, UnifiedDiff("/dev/null", change.Path, nil, change.After))
+		default:
+			fmt.Fprintf(out, "modify %s\n", change.Path)
+			fmt.Fprint(out, UnifiedDiff(change.Path, change.Path, change.Before, change.After))
+		}
+	}
+}
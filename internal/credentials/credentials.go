@@ -0,0 +1,233 @@
+package credentials
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Token struct {
+	Value    string
+	Username string
+}
+
+type Entry struct {
+	Host     string `json:"host"`
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"tokenEnv,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type fileData struct {
+	Hosts []Entry `json:"hosts"`
+}
+
+var (
+	loadOnce sync.Once
+	entries  []Entry
+	loadErr  error
+
+	hostEnvPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+	urlUserinfo    = regexp.MustCompile(`(?i)(https?://)[^/\s@]+@`)
+	authHeader     = regexp.MustCompile(`(?i)((?:authorization|private-token):\s*(?:(?:basic|bearer|token)\s+)?)\S+`)
+)
+
+var wellKnown = map[string][]string{
+	"github.com": {"GITHUB_TOKEN", "GH_TOKEN"},
+	"gitlab.com": {"GITLAB_TOKEN"},
+}
+
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".mcp-skill", "credentials.json"), nil
+}
+
+func Lookup(rawURL string) (Token, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.User != nil {
+		return Token{}, nil
+	}
+	host := canonicalHost(parsed.Hostname())
+	if value := strings.TrimSpace(os.Getenv(HostEnv(host))); value != "" {
+		return Token{Value: value}, nil
+	}
+	loaded, err := load()
+	if err != nil {
+		return Token{}, err
+	}
+	if entry, ok := match(loaded, host, parsed.Port(), parsed.Path); ok {
+		value := strings.TrimSpace(entry.Token)
+		if entry.TokenEnv != "" {
+			value = strings.TrimSpace(os.Getenv(entry.TokenEnv))
+			if value == "" {
+				return Token{}, fmt.Errorf("credentials for %s need %s, which is not set", entry.Host, entry.TokenEnv)
+			}
+		}
+		return Token{Value: value, Username: entry.Username}, nil
+	}
+	for _, name := range wellKnown[host] {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return Token{Value: value}, nil
+		}
+	}
+	return Token{}, nil
+}
+
+func HostEnv(host string) string {
+	name := hostEnvPattern.ReplaceAllString(strings.ToUpper(canonicalHost(host)), "_")
+	return "MCP_SKILL_TOKEN_" + strings.Trim(name, "_")
+}
+
+func GitEnv(repoURL string, token Token) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token.Value == "" {
+		return env
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.User != nil {
+		return env
+	}
+	username := token.Username
+	if username == "" {
+		username = "oauth2"
+		if canonicalHost(parsed.Hostname()) == "github.com" {
+			username = "x-access-token"
+		}
+	}
+	count := 0
+	if existing, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT")); err == nil && existing > 0 {
+		count = existing
+	}
+	header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+token.Value))
+	return append(env,
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.%s://%s/.extraHeader", count, parsed.Scheme, parsed.Host),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, header),
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+	)
+}
+
+func Redact(text string, tokens ...Token) string {
+	values := knownValues()
+	for _, token := range tokens {
+		values = append(values, token.Value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		if len(value) >= 4 {
+			text = strings.ReplaceAll(text, value, "***")
+		}
+	}
+	text = urlUserinfo.ReplaceAllString(text, "${1}***@")
+	return authHeader.ReplaceAllString(text, "${1}***")
+}
+
+func load() ([]Entry, error) {
+	loadOnce.Do(func() {
+		entries, loadErr = readFile()
+	})
+	return entries, loadErr
+}
+
+func readFile() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("%s is accessible by other users; run chmod 600 %s", path, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var parsed fileData
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	for i, entry := range parsed.Hosts {
+		entry.Host = strings.Trim(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(entry.Host), "https://"), "http://"), "/")
+		if entry.Host == "" {
+			return nil, fmt.Errorf("invalid %s: hosts[%d] has no host", path, i)
+		}
+		if strings.TrimSpace(entry.Token) == "" && strings.TrimSpace(entry.TokenEnv) == "" {
+			return nil, fmt.Errorf("invalid %s: %s needs a token or tokenEnv", path, entry.Host)
+		}
+		parsed.Hosts[i] = entry
+	}
+	return parsed.Hosts, nil
+}
+
+func match(loaded []Entry, host, port, path string) (Entry, bool) {
+	keys := []string{host + "/" + strings.Trim(path, "/")}
+	if port != "" {
+		keys = append([]string{host + ":" + port + "/" + strings.Trim(path, "/")}, keys...)
+	}
+	var best Entry
+	found := false
+	for _, entry := range loaded {
+		pattern := strings.ToLower(entry.Host)
+		if name, rest, ok := strings.Cut(pattern, "/"); ok {
+			pattern = canonicalHost(name) + "/" + rest
+		} else {
+			pattern = canonicalHost(pattern)
+		}
+		for _, key := range keys {
+			if (key == pattern || strings.HasPrefix(key, pattern+"/")) && (!found || len(pattern) > len(best.Host)) {
+				best, found = entry, true
+				best.Host = pattern
+			}
+		}
+	}
+	return best, found
+}
+
+func canonicalHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	switch host {
+	case "raw.githubusercontent.com", "www.github.com":
+		return "github.com"
+	}
+	return host
+}
+
+func knownValues() []string {
+	var values []string
+	for _, pair := range os.Environ() {
+		name, value, _ := strings.Cut(pair, "=")
+		if strings.HasPrefix(name, "MCP_SKILL_TOKEN_") {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+	for _, names := range wellKnown {
+		for _, name := range names {
+			values = append(values, strings.TrimSpace(os.Getenv(name)))
+		}
+	}
+	loaded, _ := load()
+	for _, entry := range loaded {
+		values = append(values, strings.TrimSpace(entry.Token))
+		if entry.TokenEnv != "" {
+			values = append(values, strings.TrimSpace(os.Getenv(entry.TokenEnv)))
+		}
+	}
+	return values
+}
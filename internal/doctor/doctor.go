@@ -0,0 +1,201 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"mcp-skill-manager/internal/registryindex"
+)
+
+const IndexStaleAfter = 7 * 24 * time.Hour
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+type Finding struct {
+	Severity Severity
+	Subject  string
+	Problem  string
+	Fix      string
+	Repair   func() error
+}
+
+type Report struct {
+	checked  int
+	findings []Finding
+}
+
+func (r *Report) Pass() {
+	r.checked++
+}
+
+func (r *Report) Add(finding Finding) {
+	r.checked++
+	r.findings = append(r.findings, finding)
+}
+
+func (r *Report) Finish(out io.Writer, fix bool) int {
+	var remaining []Finding
+	fixed := 0
+	for _, finding := range r.findings {
+		if !fix || finding.Repair == nil {
+			remaining = append(remaining, finding)
+			continue
+		}
+		if err := finding.Repair(); err != nil {
+			finding.Fix = fmt.Sprintf("automatic fix failed: %v", err)
+			finding.Repair = nil
+			remaining = append(remaining, finding)
+			continue
+		}
+		fixed++
+		fmt.Fprintf(out, "fixed    %s: %s\n", finding.Subject, finding.Problem)
+	}
+	if fixed > 0 && len(remaining) > 0 {
+		fmt.Fprintln(out)
+	}
+
+	errors, warnings, fixable := 0, 0, 0
+	for _, finding := range remaining {
+		switch finding.Severity {
+		case SeverityError:
+			errors++
+		default:
+			warnings++
+		}
+		fmt.Fprintf(out, "%-8s %s: %s\n", finding.Severity, finding.Subject, finding.Problem)
+		if finding.Fix == "" {
+			continue
+		}
+		if finding.Repair != nil {
+			fixable++
+			fmt.Fprintf(out, "         fix (--fix): %s\n", finding.Fix)
+			continue
+		}
+		fmt.Fprintf(out, "         fix: %s\n", finding.Fix)
+	}
+
+	if len(remaining) == 0 {
+		if fixed > 0 {
+			fmt.Fprintf(out, "\nsummary: %d checks, %d fixed, no problems left\n", r.checked, fixed)
+			return 0
+		}
+		fmt.Fprintf(out, "no problems found (%d checks)\n", r.checked)
+		return 0
+	}
+	parts := []string{fmt.Sprintf("%d checks", r.checked)}
+	if fixed > 0 {
+		parts = append(parts, fmt.Sprintf("%d fixed", fixed))
+	}
+	if errors > 0 {
+		parts = append(parts, plural(errors, "error"))
+	}
+	if warnings > 0 {
+		parts = append(parts, plural(warnings, "warning"))
+	}
+	fmt.Fprintf(out, "\nsummary: %s\n", strings.Join(parts, ", "))
+	if fixable > 0 {
+		fmt.Fprintf(out, "%d of them can be fixed automatically; rerun with --fix\n", fixable)
+	}
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
+
+func CheckIndex(report *Report, kind string) {
+	registries, err := registryindex.Registries()
+	if err != nil {
+		report.Add(Finding{Severity: SeverityError, Subject: "registries", Problem: err.Error()})
+		return
+	}
+	for _, reg := range registries {
+		checkRegistryIndex(report, kind, reg, len(registries) > 1)
+	}
+}
+
+func checkRegistryIndex(report *Report, kind string, reg registryindex.Registry, named bool) {
+	subject := "registry index"
+	if named {
+		subject = fmt.Sprintf("registry index %s", reg.Name)
+	}
+	lastSync, ok, err := reg.LastSync()
+	switch {
+	case err != nil:
+		report.Add(Finding{Severity: SeverityWarning, Subject: subject, Problem: err.Error(), Fix: "re-download the index", Repair: reg.Refresh})
+		return
+	case !ok:
+		report.Add(Finding{Severity: SeverityWarning, Subject: subject, Problem: "never downloaded", Fix: "download the index", Repair: reg.Refresh})
+		return
+	}
+
+	var loadErr error
+	if kind == "skill" {
+		_, loadErr = reg.LoadSkillIndex()
+	} else {
+		_, loadErr = reg.LoadMCPIndex()
+	}
+	if loadErr != nil {
+		report.Add(Finding{Severity: SeverityError, Subject: subject, Problem: fmt.Sprintf("cached %s index is unreadable: %v", kind, loadErr), Fix: "re-download the index", Repair: reg.Refresh})
+		return
+	}
+
+	if age := time.Since(lastSync); age > IndexStaleAfter {
+		report.Add(Finding{
+			Severity: SeverityWarning,
+			Subject:  subject,
+			Problem:  fmt.Sprintf("last synced %s (%d days ago)", lastSync.Local().Format(time.DateTime), int(age.Hours()/24)),
+			Fix:      "refresh the index",
+			Repair:   reg.Refresh,
+		})
+		return
+	}
+	report.Pass()
+}
+
+func CheckLocalRecords(report *Report, kind string) {
+	refs, err := registryindex.LocalRecordRefs(kind)
+	if err != nil {
+		report.Add(Finding{Severity: SeverityError, Subject: kind + " records", Problem: err.Error()})
+		return
+	}
+	for _, ref := range refs {
+		ref := ref
+		label := ref.Name
+		if ref.Registry != registryindex.DefaultRegistry {
+			label = registryindex.QualifiedName(ref.Registry, ref.Name)
+		}
+		subject := fmt.Sprintf("%s record %s", kind, label)
+		remove := func() error {
+			return registryindex.RemoveLocalRecord(kind, ref.Registry, ref.Name)
+		}
+		if _, _, err := registryindex.LoadRegistryRecord(kind, ref.Registry, ref.Name); err != nil {
+			report.Add(Finding{Severity: SeverityWarning, Subject: subject, Problem: fmt.Sprintf("unreadable: %v", err), Fix: "remove the record", Repair: remove})
+			continue
+		}
+		if !registryindex.CachedEntryExists(kind, ref.Registry, ref.Name) {
+			report.Add(Finding{
+				Severity: SeverityWarning,
+				Subject:  subject,
+				Problem:  "the cached copy it describes no longer exists",
+				Fix:      "remove the stale record",
+				Repair:   remove,
+			})
+			continue
+		}
+		report.Pass()
+	}
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, word)
+	}
+	return fmt.Sprintf("%d %ss", count, word)
+}
@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	connectTimeout        = 10 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	responseHeaderTimeout = 30 * time.Second
+	defaultTimeout        = 2 * time.Minute
+	maxAttempts           = 4
+	initialBackoff        = 500 * time.Millisecond
+	maxRetryAfter         = 30 * time.Second
+)
+
+var (
+	once      sync.Once
+	transport *http.Transport
+	buildErr  error
+)
+
+func Transport() (*http.Transport, error) {
+	once.Do(func() {
+		transport, buildErr = newTransport()
+	})
+	return transport, buildErr
+}
+
+func StreamingTransport() (*http.Transport, error) {
+	base, err := Transport()
+	if err != nil {
+		return nil, err
+	}
+	streaming := base.Clone()
+	streaming.ResponseHeaderTimeout = 0
+	return streaming, nil
+}
+
+func Do(req *http.Request) (*http.Response, error) {
+	base, err := Transport()
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: base, CheckRedirect: checkRedirect}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", UserAgent())
+	}
+	timeout := requestTimeout()
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	parent := req.Context()
+	req = req.WithContext(ctx)
+	fail := func(err error) (*http.Response, error) {
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+			return nil, fmt.Errorf("%s %s: no complete response within %s (MCP_SKILL_HTTP_TIMEOUT)", req.Method, req.URL.Redacted(), timeout)
+		}
+		return nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= maxAttempts || !retryable(resp, err) || !rewindable(req) {
+			if err != nil {
+				return fail(err)
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		wait := initialBackoff << (attempt - 1)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fail(ctx.Err())
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+const Product = "mcp-skill-manager"
+
+func Version() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+func UserAgent() string {
+	return Product + "/" + Version()
+}
+
+func newTransport() (*http.Transport, error) {
+	dialer := &net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}
+	base := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	caFile := strings.TrimSpace(os.Getenv("MCP_SKILL_CA_FILE"))
+	if caFile == "" {
+		return base, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("MCP_SKILL_CA_FILE: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("MCP_SKILL_CA_FILE: no PEM certificates found in %s", caFile)
+	}
+	base.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return base, nil
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		req.Header.Del("Private-Token")
+	}
+	return nil
+}
+
+func requestTimeout() time.Duration {
+	value := strings.TrimSpace(os.Getenv("MCP_SKILL_HTTP_TIMEOUT"))
+	if value == "" {
+		return defaultTimeout
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultTimeout
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+		var certErr *tls.CertificateVerificationError
+		var unknownAuthority x509.UnknownAuthorityError
+		var hostnameErr x509.HostnameError
+		if errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) {
+			return false
+		}
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func retryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = time.Until(at)
+	} else {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait, true
+}
@@ -5,23 +5,12 @@ import (
 	"path/filepath"
 )
 
-func CleanLocalStore() error {
-	skillRoot, err := LocalSkillStore()
-	if err != nil {
-		return err
-	}
-	if err := clearDir(skillRoot); err != nil {
-		return err
-	}
-
-	mcpRoot, err := LocalMcpStore()
-	if err != nil {
-		return err
-	}
-	if err := clearDir(mcpRoot); err != nil {
-		return err
+func ClearStores(roots []string) error {
+	for _, root := range roots {
+		if err := clearDir(root); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
@@ -35,7 +24,7 @@ func clearDir(root string) error {
 	}
 
 	for _, entry := range entries {
-		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+		if err := removeAll(filepath.Join(root, entry.Name())); err != nil {
 			return err
 		}
 	}
@@ -7,6 +7,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"mcp-skill-manager/internal/credentials"
+	"mcp-skill-manager/internal/plan"
 )
 
 type InstallRecord struct {
@@ -80,22 +83,24 @@ func InstallFromLocalStore(name string, scope string, tools []Tool, cwd string,
 	if err != nil {
 		return nil, err
 	}
-	path := filepath.Join(storeRoot, name)
+	return InstallFromDir(filepath.Join(storeRoot, name), scope, tools, cwd, force)
+}
+
+func InstallFromDir(path string, scope string, tools []Tool, cwd string, force bool) ([]InstallRecord, error) {
 	if !isExistingPath(path) {
-		return nil, fmt.Errorf("skill not found in local store: %s", name)
+		return nil, fmt.Errorf("skill not found in local store: %s", filepath.Base(path))
 	}
 	return installSkillDirs([]string{path}, scope, tools, cwd, force)
 }
 
-func CacheSkillDir(path string) (string, error) {
-	cached, err := cacheSkillDirs([]string{path})
-	if err != nil {
-		return "", err
+func ReplaceDir(src, dest string) error {
+	if err := mkdirAll(filepath.Dir(dest)); err != nil {
+		return err
 	}
-	if len(cached) == 0 {
-		return "", fmt.Errorf("skill cache failed")
+	if err := removeAll(dest); err != nil {
+		return err
 	}
-	return cached[0], nil
+	return copyDir(src, dest)
 }
 
 func installSkillDirs(skillDirs []string, scope string, tools []Tool, cwd string, force bool) ([]InstallRecord, error) {
@@ -113,12 +118,12 @@ func installSkillDirs(skillDirs []string, scope string, tools []Tool, cwd string
 				if !force {
 					return nil, fmt.Errorf("skill already exists: %s (%s)", skillName, dest)
 				}
-				if err := os.RemoveAll(dest); err != nil {
+				if err := removeAll(dest); err != nil {
 					return nil, err
 				}
 			}
 
-			if err := os.MkdirAll(root, 0o755); err != nil {
+			if err := mkdirAll(root); err != nil {
 				return nil, err
 			}
 
@@ -141,7 +146,7 @@ func cacheSkillDirs(skillDirs []string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := os.MkdirAll(storeRoot, 0o755); err != nil {
+	if err := mkdirAll(storeRoot); err != nil {
 		return nil, err
 	}
 
@@ -154,7 +159,7 @@ func cacheSkillDirs(skillDirs []string) ([]string, error) {
 			continue
 		}
 
-		if err := os.RemoveAll(dest); err != nil {
+		if err := removeAll(dest); err != nil {
 			return nil, err
 		}
 		if err := copyDir(skillDir, dest); err != nil {
@@ -170,7 +175,7 @@ func normalizeRepoURL(repo string) (string, error) {
 	if repo == "" {
 		return "", fmt.Errorf("repository is required")
 	}
-	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "git@") {
+	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "git@") || strings.HasPrefix(repo, "ssh://") {
 		return repo, nil
 	}
 	if strings.Count(repo, "/") == 1 {
@@ -203,10 +208,18 @@ func isWithinRoot(root, path string) bool {
 }
 
 func gitClone(repoURL, dest string) error {
+	token, err := credentials.Lookup(repoURL)
+	if err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "clone", "--depth", "1", repoURL, dest)
+	cmd.Env = credentials.GitEnv(repoURL, token)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone %s failed: %v", credentials.Redact(repoURL, token), err)
+	}
+	return nil
 }
 
 func findSkillDirs(root string) ([]string, error) {
@@ -237,6 +250,9 @@ func findSkillDirs(root string) ([]string, error) {
 }
 
 func copyDir(src, dst string) error {
+	if plan.Intercepts(dst) {
+		return plan.RecordCopy(src, dst)
+	}
 	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -291,3 +307,17 @@ func copyFile(src, dst string, mode fs.FileMode) error {
 
 	return out.Close()
 }
+
+func mkdirAll(path string) error {
+	if plan.Intercepts(path) {
+		return nil
+	}
+	return os.MkdirAll(path, 0o755)
+}
+
+func removeAll(path string) error {
+	if plan.Intercepts(path) {
+		return plan.RecordRemove(path)
+	}
+	return os.RemoveAll(path)
+}
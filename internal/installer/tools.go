@@ -59,7 +59,7 @@ func ParseTools(value string) ([]Tool, error) {
 		if name == "all" {
 			return allTools, nil
 		}
-		if !isSupportedTool(name) {
+		if !IsSupportedTool(name) {
 			return nil, fmt.Errorf("unknown tool: %s", item)
 		}
 		if !seen[name] {
@@ -74,7 +74,7 @@ func ParseTools(value string) ([]Tool, error) {
 	return tools, nil
 }
 
-func isSupportedTool(tool Tool) bool {
+func IsSupportedTool(tool Tool) bool {
 	for _, candidate := range allTools {
 		if candidate == tool {
 			return true
@@ -31,7 +31,7 @@ func UninstallSkill(name string, scope string, tools []Tool, cwd string, force b
 			return nil, err
 		}
 
-		if err := os.RemoveAll(dest); err != nil {
+		if err := removeAll(dest); err != nil {
 			return nil, err
 		}
 
@@ -54,7 +54,7 @@ func UninstallAll(scope string, tools []Tool, cwd string) ([]RemoveRecord, error
 
 	var records []RemoveRecord
 	for _, item := range items {
-		if err := os.RemoveAll(item.Path); err != nil {
+		if err := removeAll(item.Path); err != nil {
 			return nil, err
 		}
 		records = append(records, RemoveRecord{
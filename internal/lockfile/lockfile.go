@@ -0,0 +1,190 @@
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/manifest"
+	"mcp-skill-manager/internal/safefile"
+)
+
+const (
+	FileName = ".mcp-skill.lock.json"
+	version  = 1
+)
+
+type Lock struct {
+	Version int              `json:"version"`
+	Skills  map[string]Entry `json:"skills,omitempty"`
+	MCP     map[string]Entry `json:"mcp,omitempty"`
+}
+
+type Entry struct {
+	Repo      string `json:"repo,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Head      string `json:"head,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+	Hash      string `json:"hash"`
+}
+
+func Path(cwd string) (string, error) {
+	if cwd != "" {
+		if found, err := manifest.Find(cwd); err == nil {
+			return filepath.Join(filepath.Dir(found), FileName), nil
+		}
+	}
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, FileName), nil
+}
+
+func Load(path string) (Lock, error) {
+	data, err := safefile.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Lock{Version: version}, nil
+		}
+		return Lock{}, err
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return Lock{}, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	if lock.Version == 0 {
+		lock.Version = version
+	}
+	if lock.Version > version {
+		return Lock{}, fmt.Errorf("unsupported lockfile version %d: %s", lock.Version, path)
+	}
+	return lock, nil
+}
+
+func Save(path string, lock Lock) error {
+	lock.Version = version
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func Update(path, kind, name string, entry Entry) error {
+	return safefile.WithLock(path, func() error {
+		return update(path, kind, name, entry)
+	})
+}
+
+func update(path, kind, name string, entry Entry) error {
+	lock, err := Load(path)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case "skill":
+		if lock.Skills == nil {
+			lock.Skills = map[string]Entry{}
+		}
+		lock.Skills[name] = entry
+	case "mcp":
+		if lock.MCP == nil {
+			lock.MCP = map[string]Entry{}
+		}
+		lock.MCP[name] = entry
+	default:
+		return fmt.Errorf("unknown lock kind: %s", kind)
+	}
+	return Save(path, lock)
+}
+
+func (l Lock) Find(kind, name string) (Entry, bool) {
+	var entries map[string]Entry
+	switch kind {
+	case "skill":
+		entries = l.Skills
+	case "mcp":
+		entries = l.MCP
+	}
+	entry, ok := entries[name]
+	return entry, ok
+}
+
+func CheckFrozen(path, kind, name, head string) (Entry, error) {
+	lock, err := Load(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	locked, ok := lock.Find(kind, name)
+	if !ok {
+		return Entry{}, fmt.Errorf("%s %s is not in lockfile %s", kind, name, path)
+	}
+	if locked.Head != head {
+		return Entry{}, fmt.Errorf("%s %s registry head %s differs from lockfile head %s", kind, name, displayHead(head), displayHead(locked.Head))
+	}
+	return locked, nil
+}
+
+func HashDir(root string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, rel := range files {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00", rel)
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte{0})
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func HashJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func displayHead(head string) string {
+	if head == "" {
+		return "(none)"
+	}
+	return head
+}
@@ -0,0 +1,177 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+const DefaultFile = ".mcp-skill.json"
+
+type Manifest struct {
+	Clients []string    `json:"clients,omitempty"`
+	Scope   string      `json:"scope,omitempty"`
+	MCP     []MCPServer `json:"mcp,omitempty"`
+	Skills  []Skill     `json:"skills,omitempty"`
+
+	path string
+}
+
+type MCPServer struct {
+	Name      string            `json:"name"`
+	Source    string            `json:"source,omitempty"`
+	Transport string            `json:"transport,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Clients   []string          `json:"clients,omitempty"`
+	Scope     string            `json:"scope,omitempty"`
+}
+
+type Skill struct {
+	Name    string   `json:"name"`
+	Source  string   `json:"source,omitempty"`
+	Clients []string `json:"clients,omitempty"`
+	Scope   string   `json:"scope,omitempty"`
+}
+
+func Find(cwd string) (string, error) {
+	dir := filepath.Clean(cwd)
+	for {
+		path := filepath.Join(dir, DefaultFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s not found in %s or any parent directory", DefaultFile, cwd)
+		}
+		dir = parent
+	}
+}
+
+func Load(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m.path = abs
+
+	seen := map[string]bool{}
+	for _, server := range m.MCP {
+		name := strings.TrimSpace(server.Name)
+		if name == "" {
+			return Manifest{}, fmt.Errorf("invalid manifest %s: mcp entry missing name", path)
+		}
+		if seen[name] {
+			return Manifest{}, fmt.Errorf("invalid manifest %s: duplicate mcp entry: %s", path, name)
+		}
+		seen[name] = true
+	}
+	seen = map[string]bool{}
+	for _, item := range m.Skills {
+		name := strings.TrimSpace(item.Name)
+		if name == "" {
+			return Manifest{}, fmt.Errorf("invalid manifest %s: skill entry missing name", path)
+		}
+		if seen[name] {
+			return Manifest{}, fmt.Errorf("invalid manifest %s: duplicate skill entry: %s", path, name)
+		}
+		seen[name] = true
+	}
+	return m, nil
+}
+
+func (m Manifest) Path() string {
+	return m.path
+}
+
+func (m Manifest) Dir() string {
+	return filepath.Dir(m.path)
+}
+
+func (m Manifest) ResolvePath(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+	candidate := filepath.Join(m.Dir(), value)
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate
+	}
+	return value
+}
+
+func (m Manifest) Targets(clients []string, scope string) ([]installer.Tool, string, error) {
+	if len(clients) == 0 {
+		clients = m.Clients
+	}
+	if len(clients) == 0 {
+		return nil, "", fmt.Errorf("no clients specified")
+	}
+	tools, err := installer.ParseTools(strings.Join(clients, ","))
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(scope) == "" {
+		scope = m.Scope
+	}
+	normalized, err := NormalizeScope(scope)
+	if err != nil {
+		return nil, "", err
+	}
+	return tools, normalized, nil
+}
+
+func NormalizeScope(scope string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "", "local", "project":
+		return installer.ScopeProject, nil
+	case "global", "user":
+		return installer.ScopeUser, nil
+	default:
+		return "", fmt.Errorf("unknown scope: %s", scope)
+	}
+}
+
+func (s MCPServer) HasInlineDefinition() bool {
+	return strings.TrimSpace(s.URL) != "" || strings.TrimSpace(s.Command) != ""
+}
+
+func (s MCPServer) TransportName() string {
+	if transport := strings.TrimSpace(s.Transport); transport != "" {
+		return transport
+	}
+	if strings.TrimSpace(s.URL) != "" {
+		return "http"
+	}
+	return "stdio"
+}
+
+func (s MCPServer) SourceName() string {
+	if source := strings.TrimSpace(s.Source); source != "" {
+		return source
+	}
+	return strings.TrimSpace(s.Name)
+}
+
+func (s Skill) SourceName() string {
+	if source := strings.TrimSpace(s.Source); source != "" {
+		return source
+	}
+	return strings.TrimSpace(s.Name)
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+type ClientAdapter interface {
+	Client() installer.Tool
+	SupportsScope(scope string) bool
+	ConfigPath(scope, cwd string) (string, error)
+	Install(def Definition, scope, cwd string, force bool) (string, error)
+	Uninstall(name, scope, cwd string, force bool) (string, error)
+	List(scope, cwd string) ([]Entry, string, error)
+	Read(name, scope, cwd string) (Definition, bool, error)
+}
+
+var (
+	adaptersMu   sync.RWMutex
+	adapters     = map[installer.Tool]ClientAdapter{}
+	adapterOrder []installer.Tool
+)
+
+func init() {
+	RegisterAdapter(newClaudeAdapter())
+	RegisterAdapter(codexAdapter{})
+	RegisterAdapter(newGeminiAdapter())
+	RegisterAdapter(newOpenCodeAdapter())
+	RegisterAdapter(newCursorAdapter())
+	RegisterAdapter(newWindsurfAdapter())
+	RegisterAdapter(newRooAdapter())
+	RegisterAdapter(newKiloCodeAdapter())
+	RegisterAdapter(newCopilotAdapter())
+	RegisterAdapter(gooseAdapter{})
+}
+
+func RegisterAdapter(adapter ClientAdapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	client := adapter.Client()
+	if _, exists := adapters[client]; !exists {
+		adapterOrder = append(adapterOrder, client)
+	}
+	adapters[client] = adapter
+}
+
+func AdapterFor(client installer.Tool) (ClientAdapter, error) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	adapter, ok := adapters[client]
+	if !ok {
+		return nil, fmt.Errorf("unsupported client: %s", client)
+	}
+	return adapter, nil
+}
+
+func SupportedClients() []installer.Tool {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	clients := make([]installer.Tool, len(adapterOrder))
+	copy(clients, adapterOrder)
+	return clients
+}
+
+func SupportsClient(client installer.Tool) bool {
+	_, err := AdapterFor(client)
+	return err == nil
+}
+
+func SupportsScope(client installer.Tool, scope string) bool {
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return false
+	}
+	return adapter.SupportsScope(scope)
+}
+
+func scopedConfigPath(client installer.Tool, scope, cwd string, userPath func(home string) string, projectPath func(cwd string) string) (string, error) {
+	switch scope {
+	case installer.ScopeUser:
+		if userPath == nil {
+			return "", fmt.Errorf("%s does not support user-scoped MCP", client)
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return userPath(home), nil
+	case installer.ScopeProject:
+		if projectPath == nil {
+			return "", fmt.Errorf("%s does not support project-scoped MCP", client)
+		}
+		if cwd == "" {
+			return "", fmt.Errorf("project scope requires working directory")
+		}
+		return projectPath(cwd), nil
+	default:
+		return "", fmt.Errorf("invalid scope: %s", scope)
+	}
+}
@@ -0,0 +1,277 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/safefile"
+)
+
+const (
+	backupMetaFile   = "backup.json"
+	backupTimeLayout = "20060102-150405.000"
+	backupKeep       = 20
+)
+
+type Backup struct {
+	ID        string         `json:"id"`
+	Client    installer.Tool `json:"client"`
+	Scope     string         `json:"scope"`
+	Path      string         `json:"path"`
+	Operation string         `json:"operation"`
+	CreatedAt time.Time      `json:"createdAt"`
+
+	dir string
+}
+
+func BackupRoot() (string, error) {
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "backups"), nil
+}
+
+func ListBackups(clients []installer.Tool) ([]Backup, error) {
+	root, err := BackupRoot()
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[installer.Tool]bool{}
+	for _, client := range clients {
+		allowed[client] = true
+	}
+	clientDirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var backups []Backup
+	for _, clientDir := range clientDirs {
+		if !clientDir.IsDir() || (len(allowed) > 0 && !allowed[installer.Tool(clientDir.Name())]) {
+			continue
+		}
+		items, err := readClientBackups(filepath.Join(root, clientDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, items...)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+func FindBackup(id string) (Backup, error) {
+	client, stamp, ok := strings.Cut(strings.TrimSpace(id), "/")
+	if !ok || !installer.IsSupportedTool(installer.Tool(client)) || stamp == "" || strings.ContainsAny(stamp, `/\`) || stamp == "." || stamp == ".." {
+		return Backup{}, fmt.Errorf("invalid backup id: %s (expected <client>/<timestamp>)", id)
+	}
+	root, err := BackupRoot()
+	if err != nil {
+		return Backup{}, err
+	}
+	backup, err := loadBackup(filepath.Join(root, client, stamp))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Backup{}, fmt.Errorf("backup not found: %s", id)
+		}
+		return Backup{}, err
+	}
+	if backup.Client != installer.Tool(client) {
+		return Backup{}, fmt.Errorf("backup %s was recorded for %s", id, backup.Client)
+	}
+	return backup, nil
+}
+
+func RestoreBackup(id string) (Backup, error) {
+	backup, err := FindBackup(id)
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := checkBackupTarget(backup); err != nil {
+		return Backup{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(backup.dir, filepath.Base(backup.Path)))
+	if err != nil {
+		return Backup{}, err
+	}
+	err = safefile.WithLock(backup.Path, func() error {
+		return withBackup(backup.Client, backup.Scope, backup.Path, "restore "+backup.ID, func() error {
+			return safefile.WriteFile(backup.Path, data, 0o644)
+		})
+	})
+	if err != nil {
+		return Backup{}, err
+	}
+	return backup, nil
+}
+
+func checkBackupTarget(backup Backup) error {
+	adapter, err := AdapterFor(backup.Client)
+	if err != nil {
+		return fmt.Errorf("backup %s: %w", backup.ID, err)
+	}
+	cwd, want := "", ""
+	if backup.Scope == installer.ScopeProject {
+		sentinel := filepath.Join(string(filepath.Separator), "project")
+		template, err := adapter.ConfigPath(backup.Scope, sentinel)
+		if err != nil {
+			return fmt.Errorf("backup %s: %w", backup.ID, err)
+		}
+		rel, err := filepath.Rel(sentinel, template)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("backup %s: cannot locate the %s project config", backup.ID, backup.Client)
+		}
+		cwd = strings.TrimSuffix(filepath.Clean(backup.Path), string(filepath.Separator)+rel)
+		want = filepath.Join("<project>", rel)
+	}
+	expected, err := adapter.ConfigPath(backup.Scope, cwd)
+	if err != nil {
+		return fmt.Errorf("backup %s: %w", backup.ID, err)
+	}
+	if want == "" {
+		want = expected
+	}
+	if filepath.Clean(expected) != filepath.Clean(backup.Path) {
+		return fmt.Errorf("backup %s targets %s, which is not a %s %s-scope config (expected %s)", backup.ID, backup.Path, backup.Client, backup.Scope, want)
+	}
+	return nil
+}
+
+func withBackup(client installer.Tool, scope, path, operation string, fn func() error) error {
+	if plan.Active() {
+		return fn()
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fn()
+		}
+		return err
+	}
+	backup, err := createBackup(client, scope, path, operation, before)
+	if err != nil {
+		return fmt.Errorf("backup %s: %w", path, err)
+	}
+	fnErr := fn()
+	after, readErr := os.ReadFile(path)
+	if readErr == nil && string(after) == string(before) {
+		os.RemoveAll(backup.dir)
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	return pruneBackups(filepath.Dir(backup.dir), backupKeep)
+}
+
+func createBackup(client installer.Tool, scope, path, operation string, data []byte) (Backup, error) {
+	root, err := BackupRoot()
+	if err != nil {
+		return Backup{}, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Backup{}, err
+	}
+	now := time.Now().UTC()
+	clientDir := filepath.Join(root, string(client))
+	if err := os.MkdirAll(clientDir, 0o755); err != nil {
+		return Backup{}, err
+	}
+	stamp := now.Format(backupTimeLayout)
+	dir := filepath.Join(clientDir, stamp)
+	for i := 1; ; i++ {
+		err := os.Mkdir(dir, 0o700)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return Backup{}, err
+		}
+		stamp = fmt.Sprintf("%s-%d", now.Format(backupTimeLayout), i)
+		dir = filepath.Join(clientDir, stamp)
+	}
+
+	backup := Backup{
+		ID:        string(client) + "/" + stamp,
+		Client:    client,
+		Scope:     scope,
+		Path:      abs,
+		Operation: operation,
+		CreatedAt: now,
+		dir:       dir,
+	}
+	if err := safefile.WriteFile(filepath.Join(dir, filepath.Base(abs)), data, 0o600); err != nil {
+		return Backup{}, err
+	}
+	meta, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := safefile.WriteFile(filepath.Join(dir, backupMetaFile), meta, 0o600); err != nil {
+		return Backup{}, err
+	}
+	return backup, nil
+}
+
+func readClientBackups(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		backup, err := loadBackup(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup)
+	}
+	return backups, nil
+}
+
+func loadBackup(dir string) (Backup, error) {
+	data, err := os.ReadFile(filepath.Join(dir, backupMetaFile))
+	if err != nil {
+		return Backup{}, err
+	}
+	var backup Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return Backup{}, fmt.Errorf("invalid backup %s: %w", dir, err)
+	}
+	backup.dir = dir
+	return backup, nil
+}
+
+func pruneBackups(clientDir string, keep int) error {
+	backups, err := readClientBackups(clientDir)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	for _, backup := range backups[keep:] {
+		if err := os.RemoveAll(backup.dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
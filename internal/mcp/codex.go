@@ -2,151 +2,261 @@ package mcp
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"mcp-skill-manager/internal/installer"
 )
 
-func CodexConfigPath(scope, cwd string) (string, error) {
-	switch scope {
-	case installer.ScopeUser:
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		return filepath.Join(home, ".codex", "config.toml"), nil
-	case installer.ScopeProject:
-		return "", fmt.Errorf("codex does not support project-scoped MCP")
-	default:
-		return "", fmt.Errorf("invalid scope: %s", scope)
-	}
-}
+const codexSection = "mcp_servers"
 
-func InstallCodex(def Definition, scope, cwd string, force bool) (string, error) {
-	switch scope {
-	case installer.ScopeUser:
-		return installCodexGlobal(def, cwd, force)
-	default:
-		return "", fmt.Errorf("invalid scope: %s", scope)
-	}
+type codexAdapter struct{}
+
+func (codexAdapter) Client() installer.Tool {
+	return installer.ToolCodex
 }
 
-func UninstallCodex(name, scope, cwd string, force bool) (string, error) {
-	switch scope {
-	case installer.ScopeUser:
-		return uninstallCodexGlobal(name, cwd, force)
-	default:
-		return "", fmt.Errorf("invalid scope: %s", scope)
-	}
+func (codexAdapter) SupportsScope(scope string) bool {
+	return scope == installer.ScopeUser
 }
 
-func ListCodex(scope, cwd string) ([]Entry, string, error) {
-	switch scope {
-	case installer.ScopeUser:
-		return listCodexGlobal(cwd)
-	default:
-		return nil, "", fmt.Errorf("invalid scope: %s", scope)
-	}
+func (codexAdapter) ConfigPath(scope, cwd string) (string, error) {
+	return scopedConfigPath(installer.ToolCodex, scope, cwd, func(home string) string {
+		return filepath.Join(home, ".codex", "config.toml")
+	}, nil)
 }
 
-func installCodexGlobal(def Definition, cwd string, force bool) (string, error) {
-	path, err := CodexConfigPath(installer.ScopeUser, cwd)
+func (a codexAdapter) Install(def Definition, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
 	if err != nil {
 		return "", err
 	}
-	blocks, err := parseTomlBlocks(path)
+	doc, err := loadTomlDocument(path)
 	if err != nil {
 		return "", err
 	}
-
-	updated := false
-	for i, block := range blocks {
-		if block.kind == "mcp" && block.name == def.Name {
-			if !force {
-				return "", fmt.Errorf("server already exists: %s", def.Name)
-			}
-			blocks[i].lines = formatTomlEntry(def)
-			updated = true
-			break
-		}
+	if _, exists := doc.tables([]string{codexSection})[def.Name]; exists && !force {
+		return "", fmt.Errorf("server already exists: %s", def.Name)
 	}
-
-	if !updated {
-		if len(blocks) > 0 && len(blocks[len(blocks)-1].lines) > 0 {
-			blocks = append(blocks, tomlBlock{kind: "other", lines: []string{""}})
-		}
-		blocks = append(blocks, tomlBlock{kind: "mcp", name: def.Name, lines: formatTomlEntry(def)})
+	serverPath := []string{codexSection, def.Name}
+	existing, err := doc.rawValues(serverPath)
+	if err != nil {
+		return "", err
 	}
-
-	if err := writeTomlBlocks(path, blocks); err != nil {
+	arrays, err := doc.arrayTables(serverPath)
+	if err != nil {
+		return "", err
+	}
+	block := formatCodexEntry(def, existing)
+	if arrays != "" {
+		block += "\n" + arrays
+	}
+	if err := doc.replace(serverPath, block); err != nil {
+		return "", err
+	}
+	if err := doc.save(path); err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
-func uninstallCodexGlobal(name, cwd string, force bool) (string, error) {
-	path, err := CodexConfigPath(installer.ScopeUser, cwd)
+func (a codexAdapter) Uninstall(name, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
 	if err != nil {
 		return "", err
 	}
-	blocks, err := parseTomlBlocks(path)
+	doc, err := loadTomlDocument(path)
 	if err != nil {
 		return "", err
 	}
-
-	var updated []tomlBlock
-	found := false
-	for _, block := range blocks {
-		if block.kind == "mcp" && block.name == name {
-			found = true
-			continue
+	if _, exists := doc.tables([]string{codexSection})[name]; !exists {
+		if force {
+			return path, nil
 		}
-		updated = append(updated, block)
-	}
-
-	if !found && !force {
 		return "", fmt.Errorf("server not found: %s", name)
 	}
-	if err := writeTomlBlocks(path, updated); err != nil {
+	if err := doc.replace([]string{codexSection, name}, ""); err != nil {
+		return "", err
+	}
+	if err := doc.save(path); err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
-func listCodexGlobal(cwd string) ([]Entry, string, error) {
-	path, err := CodexConfigPath(installer.ScopeUser, cwd)
+func (a codexAdapter) List(scope, cwd string) ([]Entry, string, error) {
+	path, err := a.ConfigPath(scope, cwd)
 	if err != nil {
 		return nil, "", err
 	}
-	blocks, err := parseTomlBlocks(path)
+	doc, err := loadTomlDocument(path)
 	if err != nil {
 		return nil, "", err
 	}
+	servers := map[string]any{}
+	for name, server := range doc.tables([]string{codexSection}) {
+		servers[name] = server
+	}
+	return extractEntries(servers), path, nil
+}
+
+func (a codexAdapter) Read(name, scope, cwd string) (Definition, bool, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	doc, err := loadTomlDocument(path)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	server, ok := doc.tables([]string{codexSection})[name]
+	if !ok {
+		return Definition{}, false, nil
+	}
+	def, err := fromCodexServer(name, server)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	return def, true, nil
+}
+
+func formatCodexEntry(def Definition, existing []tomlStatement) string {
+	env, envVars := forwardedEnv(def.Env)
+	headers, bearerVar, envHeaders := splitCodexHeaders(def.Headers)
+	lines := []string{"[" + formatTomlPath(codexSection, def.Name) + "]"}
+	if def.Transport == "http" {
+		lines = append(lines, "url = "+formatTomlString(def.URL))
+		if bearerVar != "" {
+			lines = append(lines, "bearer_token_env_var = "+formatTomlString(bearerVar))
+		}
+	} else {
+		lines = append(lines, "command = "+formatTomlString(def.Command))
+		if len(def.Args) > 0 {
+			lines = append(lines, "args = "+formatTomlStringArray(def.Args))
+		}
+		if len(envVars) > 0 {
+			lines = append(lines, "env_vars = "+formatTomlStringArray(envVars))
+		}
+	}
+	if def.StartupTimeoutSec > 0 {
+		lines = append(lines, "startup_timeout_sec = "+formatTomlNumber(def.StartupTimeoutSec))
+	}
+	if def.ToolTimeoutSec > 0 {
+		lines = append(lines, "tool_timeout_sec = "+formatTomlNumber(def.ToolTimeoutSec))
+	}
+	if def.Disabled {
+		lines = append(lines, "enabled = false")
+	}
 
-	var entries []Entry
-	for _, block := range blocks {
-		if block.kind != "mcp" {
+	for _, stmt := range existing {
+		rel := stmt.path[2:]
+		switch rel[0] {
+		case "command", "args", "url", "env", "http_headers", "env_vars", "bearer_token_env_var", "env_http_headers":
 			continue
+		case "startup_timeout_sec":
+			if def.StartupTimeoutSec > 0 {
+				continue
+			}
+		case "tool_timeout_sec":
+			if def.ToolTimeoutSec > 0 {
+				continue
+			}
+		case "enabled":
+			if def.Disabled {
+				continue
+			}
 		}
-		entries = append(entries, Entry{
-			Name:      block.name,
-			Transport: detectTomlTransport(block.lines),
-		})
+		lines = append(lines, formatTomlPath(rel...)+" = "+stmt.raw)
+	}
+
+	if def.Transport != "http" && len(env) > 0 {
+		lines = append(lines, formatTomlStringTable(formatTomlPath(codexSection, def.Name, "env"), env)...)
+	}
+	if def.Transport == "http" && len(headers) > 0 {
+		lines = append(lines, formatTomlStringTable(formatTomlPath(codexSection, def.Name, "http_headers"), headers)...)
+	}
+	if def.Transport == "http" && len(envHeaders) > 0 {
+		lines = append(lines, formatTomlStringTable(formatTomlPath(codexSection, def.Name, "env_http_headers"), envHeaders)...)
 	}
-	return entries, path, nil
+	return strings.Join(lines, "\n") + "\n"
 }
 
-func detectTomlTransport(lines []string) string {
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "url ") || strings.HasPrefix(trimmed, "url=") {
-			return "http"
+func splitCodexHeaders(headers map[string]string) (map[string]string, string, map[string]string) {
+	rest := map[string]string{}
+	envHeaders := map[string]string{}
+	bearerVar := ""
+	for key, value := range headers {
+		match := secretReferencePattern.FindStringSubmatchIndex(value)
+		switch {
+		case match == nil:
+			rest[key] = value
+		case match[0] == 0 && match[1] == len(value):
+			envHeaders[key] = value[match[2]:match[3]]
+		case strings.EqualFold(key, "Authorization") && strings.EqualFold(value[:match[0]], "Bearer ") && match[1] == len(value):
+			bearerVar = value[match[2]:match[3]]
+		default:
+			rest[key] = value
 		}
-		if strings.HasPrefix(trimmed, "command ") || strings.HasPrefix(trimmed, "command=") {
-			return "stdio"
+	}
+	if len(rest) == 0 {
+		rest = nil
+	}
+	if len(envHeaders) == 0 {
+		envHeaders = nil
+	}
+	return rest, bearerVar, envHeaders
+}
+
+func (codexAdapter) unsupportedReferences(def Definition) []string {
+	env, _ := forwardedEnv(def.Env)
+	headers, _, _ := splitCodexHeaders(def.Headers)
+	values := append([]string{def.URL, def.Command}, def.Args...)
+	if def.Transport == "http" {
+		for _, value := range headers {
+			values = append(values, value)
+		}
+	} else {
+		for _, value := range env {
+			values = append(values, value)
+		}
+	}
+	return referencesIn(values...)
+}
+
+func fromCodexServer(name string, server map[string]any) (Definition, error) {
+	def := Definition{
+		Name:              name,
+		Transport:         detectTransport(server),
+		URL:               stringValue(server["url"]),
+		Command:           stringValue(server["command"]),
+		Args:              stringSlice(server["args"]),
+		Env:               stringMap(server["env"]),
+		Headers:           stringMap(server["http_headers"]),
+		StartupTimeoutSec: tomlNumber(server["startup_timeout_sec"]),
+		ToolTimeoutSec:    tomlNumber(server["tool_timeout_sec"]),
+	}
+	if enabled, ok := server["enabled"].(bool); ok && !enabled {
+		def.Disabled = true
+	}
+	for _, name := range stringSlice(server["env_vars"]) {
+		if def.Env == nil {
+			def.Env = map[string]string{}
+		}
+		if _, exists := def.Env[name]; !exists {
+			def.Env[name] = SecretReference(name)
+		}
+	}
+	if name := stringValue(server["bearer_token_env_var"]); name != "" {
+		if def.Headers == nil {
+			def.Headers = map[string]string{}
+		}
+		def.Headers["Authorization"] = "Bearer " + SecretReference(name)
+	}
+	for header, name := range stringMap(server["env_http_headers"]) {
+		if def.Headers == nil {
+			def.Headers = map[string]string{}
 		}
+		def.Headers[header] = SecretReference(name)
 	}
-	return ""
+	return normalizeDefinition(def, "")
 }
@@ -0,0 +1,22 @@
+package mcp
+
+import (
+	"path/filepath"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func newCopilotAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolCopilot,
+		userPath: func(home string) string {
+			return filepath.Join(vscodeUserDir(home), "mcp.json")
+		},
+		projectPath: func(cwd string) string {
+			return filepath.Join(cwd, ".vscode", "mcp.json")
+		},
+		section:    "servers",
+		toServer:   toClaudeServer,
+		fromServer: fromClaudeServer,
+	}
+}
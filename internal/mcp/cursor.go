@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"path/filepath"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func newCursorAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolCursor,
+		userPath: func(home string) string {
+			return filepath.Join(home, ".cursor", "mcp.json")
+		},
+		projectPath: func(cwd string) string {
+			return filepath.Join(cwd, ".cursor", "mcp.json")
+		},
+		section:    "mcpServers",
+		toServer:   toCursorServer,
+		fromServer: fromClaudeServer,
+	}
+}
+
+func toCursorServer(def Definition) map[string]any {
+	if def.Transport == "http" {
+		server := map[string]any{
+			"url": def.URL,
+		}
+		if len(def.Headers) > 0 {
+			server["headers"] = def.Headers
+		}
+		return server
+	}
+	args := def.Args
+	if args == nil {
+		args = []string{}
+	}
+	server := map[string]any{
+		"command": def.Command,
+		"args":    args,
+	}
+	if len(def.Env) > 0 {
+		server["env"] = def.Env
+	}
+	return server
+}
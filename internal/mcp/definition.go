@@ -9,23 +9,29 @@ import (
 )
 
 type Definition struct {
-	Name      string
-	Transport string
-	URL       string
-	Command   string
-	Args      []string
-	Env       map[string]string
-	Headers   map[string]string
+	Name              string            `json:"name"`
+	Transport         string            `json:"transport"`
+	URL               string            `json:"url,omitempty"`
+	Command           string            `json:"command,omitempty"`
+	Args              []string          `json:"args,omitempty"`
+	Env               map[string]string `json:"env,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	StartupTimeoutSec float64           `json:"startupTimeoutSec,omitempty"`
+	ToolTimeoutSec    float64           `json:"toolTimeoutSec,omitempty"`
+	Disabled          bool              `json:"disabled,omitempty"`
 }
 
 type definitionFile struct {
-	Transport string            `json:"transport"`
-	Type      string            `json:"type"`
-	URL       string            `json:"url"`
-	Command   string            `json:"command"`
-	Args      []string          `json:"args"`
-	Env       map[string]string `json:"env"`
-	Headers   map[string]string `json:"headers"`
+	Transport         string            `json:"transport"`
+	Type              string            `json:"type"`
+	URL               string            `json:"url"`
+	Command           string            `json:"command"`
+	Args              []string          `json:"args"`
+	Env               map[string]string `json:"env"`
+	Headers           map[string]string `json:"headers"`
+	StartupTimeoutSec float64           `json:"startupTimeoutSec,omitempty"`
+	ToolTimeoutSec    float64           `json:"toolTimeoutSec,omitempty"`
+	Disabled          bool              `json:"disabled,omitempty"`
 }
 
 func LoadDefinitionFromFile(path string) (Definition, error) {
@@ -41,13 +47,16 @@ func LoadDefinitionFromFile(path string) (Definition, error) {
 
 	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 	return normalizeDefinition(Definition{
-		Name:      name,
-		Transport: raw.Transport,
-		URL:       raw.URL,
-		Command:   raw.Command,
-		Args:      raw.Args,
-		Env:       raw.Env,
-		Headers:   raw.Headers,
+		Name:              name,
+		Transport:         raw.Transport,
+		URL:               raw.URL,
+		Command:           raw.Command,
+		Args:              raw.Args,
+		Env:               raw.Env,
+		Headers:           raw.Headers,
+		StartupTimeoutSec: raw.StartupTimeoutSec,
+		ToolTimeoutSec:    raw.ToolTimeoutSec,
+		Disabled:          raw.Disabled,
 	}, raw.Type)
 }
 
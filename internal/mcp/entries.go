@@ -32,13 +32,22 @@ func detectTransport(server map[string]any) string {
 			return strings.ToLower(value)
 		case "local":
 			return "stdio"
-		case "remote":
+		case "remote", "streamable-http", "streamable_http", "sse":
 			return "http"
 		}
 	}
 	if _, ok := server["url"]; ok {
 		return "http"
 	}
+	if _, ok := server["serverUrl"]; ok {
+		return "http"
+	}
+	if _, ok := server["uri"]; ok {
+		return "http"
+	}
+	if _, ok := server["cmd"]; ok {
+		return "stdio"
+	}
 	if _, ok := server["command"]; ok {
 		return "stdio"
 	}
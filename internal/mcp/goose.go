@@ -0,0 +1,220 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+const gooseSection = "extensions"
+
+const gooseDefaultTimeout = 300
+
+var gooseOwnedFields = map[string]bool{
+	"type": true, "cmd": true, "args": true, "envs": true, "env_keys": true, "uri": true, "headers": true,
+}
+
+type gooseAdapter struct{}
+
+func (gooseAdapter) Client() installer.Tool {
+	return installer.ToolGoose
+}
+
+func (gooseAdapter) SupportsScope(scope string) bool {
+	return scope == installer.ScopeUser
+}
+
+func (gooseAdapter) ConfigPath(scope, cwd string) (string, error) {
+	return scopedConfigPath(installer.ToolGoose, scope, cwd, gooseConfigPath, nil)
+}
+
+func (a gooseAdapter) Install(def Definition, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return "", err
+	}
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		return "", err
+	}
+	if entry, exists := doc.findEntry(gooseSection, def.Name); exists {
+		if isGooseUnmanaged(doc.entryValue(entry)) {
+			return "", fmt.Errorf("%s is a builtin goose extension and cannot be replaced", def.Name)
+		}
+		if !force {
+			return "", fmt.Errorf("server already exists: %s", def.Name)
+		}
+	}
+	if err := doc.setEntry(gooseSection, def.Name, toGooseFields(def), gooseOwnedFields); err != nil {
+		return "", err
+	}
+	if err := doc.save(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a gooseAdapter) Uninstall(name, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return "", err
+	}
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		return "", err
+	}
+	if entry, exists := doc.findEntry(gooseSection, name); exists && isGooseUnmanaged(doc.entryValue(entry)) {
+		return "", fmt.Errorf("%s is a builtin goose extension and cannot be removed", name)
+	}
+	if !doc.removeEntry(gooseSection, name) {
+		if force {
+			return path, nil
+		}
+		return "", fmt.Errorf("server not found: %s", name)
+	}
+	if err := doc.save(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a gooseAdapter) List(scope, cwd string) ([]Entry, string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return nil, "", err
+	}
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		return nil, "", err
+	}
+	servers := map[string]any{}
+	for _, entry := range doc.entries(gooseSection) {
+		if value := doc.entryValue(entry); !isGooseUnmanaged(value) {
+			servers[entry.name] = value
+		}
+	}
+	return extractEntries(servers), path, nil
+}
+
+func (a gooseAdapter) Read(name, scope, cwd string) (Definition, bool, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	entry, ok := doc.findEntry(gooseSection, name)
+	if !ok || isGooseUnmanaged(doc.entryValue(entry)) {
+		return Definition{}, false, nil
+	}
+	def, err := fromGooseServer(name, doc.entryValue(entry))
+	if err != nil {
+		return Definition{}, false, err
+	}
+	return def, true, nil
+}
+
+func gooseConfigPath(home string) string {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "Block", "goose", "config", "config.yaml")
+		}
+		return filepath.Join(home, "AppData", "Roaming", "Block", "goose", "config", "config.yaml")
+	}
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "goose", "config.yaml")
+	}
+	return filepath.Join(home, ".config", "goose", "config.yaml")
+}
+
+func toGooseFields(def Definition) []yamlField {
+	fields := []yamlField{
+		{key: "enabled", value: true},
+		{key: "name", value: def.Name},
+	}
+	if def.Transport == "http" {
+		headers := def.Headers
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		return append(fields,
+			yamlField{key: "type", value: "streamable_http"},
+			yamlField{key: "uri", value: def.URL},
+			yamlField{key: "headers", value: headers},
+			yamlField{key: "envs", value: map[string]string{}},
+			yamlField{key: "env_keys", value: []string{}},
+			yamlField{key: "description", value: ""},
+			yamlField{key: "timeout", value: gooseDefaultTimeout},
+		)
+	}
+	envs, envKeys := forwardedEnv(def.Env)
+	if envs == nil {
+		envs = map[string]string{}
+	}
+	if envKeys == nil {
+		envKeys = []string{}
+	}
+	args := def.Args
+	if args == nil {
+		args = []string{}
+	}
+	return append(fields,
+		yamlField{key: "type", value: "stdio"},
+		yamlField{key: "cmd", value: def.Command},
+		yamlField{key: "args", value: args},
+		yamlField{key: "envs", value: envs},
+		yamlField{key: "env_keys", value: envKeys},
+		yamlField{key: "description", value: ""},
+		yamlField{key: "timeout", value: gooseDefaultTimeout},
+	)
+}
+
+func (gooseAdapter) unsupportedReferences(def Definition) []string {
+	values := append([]string{def.URL, def.Command}, def.Args...)
+	if def.Transport == "http" {
+		for _, value := range def.Headers {
+			values = append(values, value)
+		}
+	} else {
+		env, _ := forwardedEnv(def.Env)
+		for _, value := range env {
+			values = append(values, value)
+		}
+	}
+	return referencesIn(values...)
+}
+
+func fromGooseServer(name string, server map[string]any) (Definition, error) {
+	transport := detectTransport(server)
+	env := stringMap(server["envs"])
+	for _, key := range stringSlice(server["env_keys"]) {
+		if env == nil {
+			env = map[string]string{}
+		}
+		if _, exists := env[key]; !exists {
+			env[key] = SecretReference(key)
+		}
+	}
+	return normalizeDefinition(Definition{
+		Name:      name,
+		Transport: transport,
+		URL:       stringValue(server["uri"]),
+		Command:   stringValue(server["cmd"]),
+		Args:      stringSlice(server["args"]),
+		Env:       env,
+		Headers:   stringMap(server["headers"]),
+	}, "")
+}
+
+func isGooseUnmanaged(server map[string]any) bool {
+	switch stringValue(server["type"]) {
+	case "builtin", "platform", "frontend":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
+)
+
+type SavedInput struct {
+	Value  string `json:"value,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type SavedInputs struct {
+	Inputs    map[string]SavedInput `json:"inputs"`
+	UpdatedAt string                `json:"updatedAt,omitempty"`
+}
+
+type savedInputsFile map[string]SavedInputs
+
+func SavedInputsPath(name string) (string, error) {
+	if name == "" || strings.Contains(name, "..") || strings.ContainsAny(name, `/\:`) {
+		return "", fmt.Errorf("invalid server name %q", name)
+	}
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "inputs", name+".json"), nil
+}
+
+func SavedInputsKey(scope, cwd string) string {
+	if scope != installer.ScopeProject {
+		return installer.ScopeUser
+	}
+	if abs, err := filepath.Abs(cwd); err == nil {
+		cwd = abs
+	}
+	return installer.ScopeProject + ":" + cwd
+}
+
+func LoadSavedInputs(name, scope, cwd string) (SavedInputs, bool, error) {
+	path, err := SavedInputsPath(name)
+	if err != nil {
+		return SavedInputs{}, false, err
+	}
+	file, err := loadSavedInputsFile(path)
+	if err != nil {
+		return SavedInputs{}, false, err
+	}
+	saved, ok := file[SavedInputsKey(scope, cwd)]
+	return saved, ok, nil
+}
+
+func SavedInputScopes(name string) ([]string, error) {
+	path, err := SavedInputsPath(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := loadSavedInputsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(file))
+	for key := range file {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func UpdateSavedInputs(name, scope, cwd string, fn func(inputs map[string]SavedInput) error) error {
+	path, err := SavedInputsPath(name)
+	if err != nil {
+		return err
+	}
+	return safefile.WithLock(path, func() error {
+		file, err := loadSavedInputsFile(path)
+		if err != nil {
+			return err
+		}
+		key := SavedInputsKey(scope, cwd)
+		inputs := map[string]SavedInput{}
+		for input, saved := range file[key].Inputs {
+			inputs[input] = saved
+		}
+		if err := fn(inputs); err != nil {
+			return err
+		}
+		if len(inputs) == 0 {
+			delete(file, key)
+		} else {
+			file[key] = SavedInputs{Inputs: inputs, UpdatedAt: time.Now().UTC().Format(time.RFC3339)}
+		}
+		if len(file) == 0 {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				return nil
+			}
+			return safefile.RemoveAll(path)
+		}
+		data, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return err
+		}
+		return safefile.WriteFile(path, append(data, '\n'), 0o600)
+	})
+}
+
+func loadSavedInputsFile(path string) (savedInputsFile, error) {
+	data, err := safefile.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return savedInputsFile{}, nil
+		}
+		return nil, err
+	}
+	var file savedInputsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid saved inputs %s: %w", path, err)
+	}
+	if file == nil {
+		file = savedInputsFile{}
+	}
+	return file, nil
+}
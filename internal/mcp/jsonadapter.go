@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+type jsonAdapter struct {
+	client      installer.Tool
+	userPath    func(home string) string
+	projectPath func(cwd string) string
+	section     string
+	toServer    func(def Definition) map[string]any
+	fromServer  func(name string, server map[string]any) (Definition, error)
+	defaults    map[string]any
+	references  referenceStyle
+}
+
+func (a jsonAdapter) Client() installer.Tool {
+	return a.client
+}
+
+func (a jsonAdapter) SupportsScope(scope string) bool {
+	switch scope {
+	case installer.ScopeUser:
+		return a.userPath != nil
+	case installer.ScopeProject:
+		return a.projectPath != nil
+	default:
+		return false
+	}
+}
+
+func (a jsonAdapter) ConfigPath(scope, cwd string) (string, error) {
+	return scopedConfigPath(a.client, scope, cwd, a.userPath, a.projectPath)
+}
+
+func (a jsonAdapter) Install(def Definition, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return "", err
+	}
+	config, err := loadJSONConfig(path)
+	if err != nil {
+		return "", err
+	}
+	servers := ensureMap(config, a.section)
+	if _, exists := servers[def.Name]; exists && !force {
+		return "", fmt.Errorf("server already exists: %s", def.Name)
+	}
+	src, err := readJSONSource(path)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(a.defaults))
+	for key := range a.defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := config[key]; ok {
+			continue
+		}
+		if src, err = setJSONCValue(src, []string{key}, a.defaults[key]); err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	src, err = setJSONCValue(src, []string{a.section, def.Name}, a.toServer(a.references.write(def)))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if err := writeJSONSource(path, src); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a jsonAdapter) Uninstall(name, scope, cwd string, force bool) (string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return "", err
+	}
+	config, err := loadJSONConfig(path)
+	if err != nil {
+		return "", err
+	}
+	servers := ensureMap(config, a.section)
+	if _, exists := servers[name]; !exists {
+		if force {
+			return path, nil
+		}
+		return "", fmt.Errorf("server not found: %s", name)
+	}
+	src, err := readJSONSource(path)
+	if err != nil {
+		return "", err
+	}
+	src, _, err = removeJSONCValue(src, []string{a.section, name})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if err := writeJSONSource(path, src); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a jsonAdapter) List(scope, cwd string) ([]Entry, string, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return nil, "", err
+	}
+	config, err := loadJSONConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+	servers := ensureMap(config, a.section)
+	return extractEntries(servers), path, nil
+}
+
+func (a jsonAdapter) Read(name, scope, cwd string) (Definition, bool, error) {
+	path, err := a.ConfigPath(scope, cwd)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	config, err := loadJSONConfig(path)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	servers := ensureMap(config, a.section)
+	raw, ok := servers[name]
+	if !ok {
+		return Definition{}, false, nil
+	}
+	server, ok := raw.(map[string]any)
+	if !ok {
+		return Definition{}, false, fmt.Errorf("invalid server entry: %s", name)
+	}
+	def, err := a.fromServer(name, server)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	return a.references.read(def), true, nil
+}
+
+func stringValue(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+func stringSlice(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+func stringMap(value any) map[string]string {
+	items, ok := value.(map[string]any)
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(items))
+	for key, item := range items {
+		switch typed := item.(type) {
+		case string:
+			result[key] = typed
+		default:
+			result[key] = fmt.Sprint(typed)
+		}
+	}
+	return result
+}
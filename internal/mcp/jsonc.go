@@ -0,0 +1,508 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type jsoncNode struct {
+	kind    byte
+	start   int
+	end     int
+	members []jsoncMember
+}
+
+type jsoncMember struct {
+	key   string
+	start int
+	value *jsoncNode
+	comma int
+}
+
+type jsoncParser struct {
+	src string
+	pos int
+}
+
+type jsoncEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+func parseJSONC(src string) (*jsoncNode, error) {
+	p := &jsoncParser{src: src}
+	p.skip()
+	if p.pos >= len(src) {
+		return nil, fmt.Errorf("empty JSON document")
+	}
+	node, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skip()
+	if p.pos < len(src) {
+		return nil, p.errorf("unexpected %q after document", src[p.pos])
+	}
+	return node, nil
+}
+
+func (p *jsoncParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("invalid JSON at line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *jsoncParser) skip() {
+	for p.pos < len(p.src) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end == -1 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end == -1 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsoncParser) parseValue() (*jsoncNode, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of document")
+	}
+	switch p.src[p.pos] {
+	case '{':
+		return p.parseObject()
+	case '[':
+		return p.parseArray()
+	case '"':
+		start := p.pos
+		if _, err := p.parseString(); err != nil {
+			return nil, err
+		}
+		return &jsoncNode{kind: 's', start: start, end: p.pos}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(",:]} \t\r\n/", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	var scalar any
+	if err := json.Unmarshal([]byte(p.src[start:p.pos]), &scalar); err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid value %q", p.src[start:start+min(len(p.src)-start, 20)])
+	}
+	return &jsoncNode{kind: 's', start: start, end: p.pos}, nil
+}
+
+func (p *jsoncParser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			var value string
+			if err := json.Unmarshal([]byte(p.src[start:p.pos]), &value); err != nil {
+				p.pos = start
+				return "", p.errorf("invalid string")
+			}
+			return value, nil
+		case '\n':
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		default:
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsoncParser) parseObject() (*jsoncNode, error) {
+	node := &jsoncNode{kind: '{', start: p.pos}
+	p.pos++
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated object")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			node.end = p.pos
+			return node, nil
+		}
+		if p.src[p.pos] != '"' {
+			return nil, p.errorf("expected object key")
+		}
+		member := jsoncMember{start: p.pos, comma: -1}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		member.key = key
+		p.skip()
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("expected : after key %q", key)
+		}
+		p.pos++
+		p.skip()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		member.value = value
+		p.skip()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			member.comma = p.pos
+			p.pos++
+		} else if p.pos < len(p.src) && p.src[p.pos] != '}' {
+			return nil, p.errorf("expected , or } in object")
+		}
+		node.members = append(node.members, member)
+	}
+}
+
+func (p *jsoncParser) parseArray() (*jsoncNode, error) {
+	node := &jsoncNode{kind: '[', start: p.pos}
+	p.pos++
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			node.end = p.pos
+			return node, nil
+		}
+		if _, err := p.parseValue(); err != nil {
+			return nil, err
+		}
+		p.skip()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+		} else if p.pos < len(p.src) && p.src[p.pos] != ']' {
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (n *jsoncNode) member(key string) (int, bool) {
+	for i := len(n.members) - 1; i >= 0; i-- {
+		if n.members[i].key == key {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func setJSONCValue(src string, path []string, value any) (string, error) {
+	if strings.TrimSpace(src) == "" {
+		src = "{}\n"
+	}
+	root, err := parseJSONC(src)
+	if err != nil {
+		return "", err
+	}
+	if root.kind != '{' {
+		return "", fmt.Errorf("JSON document is not an object")
+	}
+	unit := detectJSONIndent(src)
+	newline := detectJSONNewline(src)
+	if isSingleLineJSONC(src, root) {
+		unit = ""
+	}
+	node := root
+	for depth, key := range path {
+		idx, ok := node.member(key)
+		if !ok {
+			var nested any = value
+			for i := len(path) - 1; i > depth; i-- {
+				nested = map[string]any{path[i]: nested}
+			}
+			edits, err := insertJSONCMember(src, node, key, nested, unit, newline)
+			if err != nil {
+				return "", err
+			}
+			return applyJSONCEdits(src, edits)
+		}
+		child := node.members[idx].value
+		if depth == len(path)-1 || child.kind != '{' {
+			var nested any = value
+			for i := len(path) - 1; i > depth; i-- {
+				nested = map[string]any{path[i]: nested}
+			}
+			indent := lineIndent(src, node.members[idx].start)
+			text, err := renderJSONCValue(nested, indent, unit, newline)
+			if err != nil {
+				return "", err
+			}
+			return applyJSONCEdits(src, []jsoncEdit{{start: child.start, end: child.end, text: text}})
+		}
+		node = child
+	}
+	return src, nil
+}
+
+func removeJSONCValue(src string, path []string) (string, bool, error) {
+	if strings.TrimSpace(src) == "" {
+		return src, false, nil
+	}
+	root, err := parseJSONC(src)
+	if err != nil {
+		return "", false, err
+	}
+	node := root
+	for depth, key := range path {
+		if node.kind != '{' {
+			return src, false, nil
+		}
+		idx, ok := node.member(key)
+		if !ok {
+			return src, false, nil
+		}
+		if depth < len(path)-1 {
+			node = node.members[idx].value
+			continue
+		}
+		updated, err := applyJSONCEdits(src, removeJSONCMember(src, node, idx))
+		return updated, err == nil, err
+	}
+	return src, false, nil
+}
+
+func insertJSONCMember(src string, obj *jsoncNode, key string, value any, unit, newline string) ([]jsoncEdit, error) {
+	indent := lineIndent(src, obj.start) + unit
+	if len(obj.members) > 0 {
+		indent = lineIndent(src, obj.members[0].start)
+	}
+	text, err := renderJSONCValue(value, indent, unit, newline)
+	if err != nil {
+		return nil, err
+	}
+	keyText, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	member := string(keyText) + ": " + text
+
+	if unit == "" {
+		if len(obj.members) == 0 {
+			return []jsoncEdit{{start: obj.start + 1, end: obj.end - 1, text: member}}, nil
+		}
+		last := obj.members[len(obj.members)-1]
+		if last.comma >= 0 {
+			return []jsoncEdit{{start: last.comma + 1, end: last.comma + 1, text: " " + member + ","}}, nil
+		}
+		return []jsoncEdit{{start: last.value.end, end: last.value.end, text: ", " + member}}, nil
+	}
+
+	if len(obj.members) == 0 {
+		if onlyJSONCWhitespace(src[obj.start+1 : obj.end-1]) {
+			closeIndent := lineIndent(src, obj.start)
+			return []jsoncEdit{{start: obj.start + 1, end: obj.end - 1, text: newline + indent + member + newline + closeIndent}}, nil
+		}
+		return []jsoncEdit{{start: obj.start + 1, end: obj.start + 1, text: newline + indent + member}}, nil
+	}
+
+	last := obj.members[len(obj.members)-1]
+	if last.comma >= 0 {
+		pos := lineEndAfter(src, last.comma+1)
+		return []jsoncEdit{{start: pos, end: pos, text: newline + indent + member + ","}}, nil
+	}
+	pos := lineEndAfter(src, last.value.end)
+	edits := []jsoncEdit{{start: last.value.end, end: last.value.end, text: ","}}
+	return append(edits, jsoncEdit{start: pos, end: pos, text: newline + indent + member}), nil
+}
+
+func removeJSONCMember(src string, obj *jsoncNode, idx int) []jsoncEdit {
+	member := obj.members[idx]
+	start := member.start
+	end := member.value.end
+	if member.comma >= 0 {
+		end = member.comma + 1
+	}
+	end = trailingJSONCComment(src, end)
+
+	if len(obj.members) == 1 && onlyJSONCWhitespace(src[obj.start+1:start]) && onlyJSONCWhitespace(src[end:obj.end-1]) {
+		return []jsoncEdit{{start: obj.start, end: obj.end, text: "{}"}}
+	}
+
+	lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+	if strings.TrimSpace(src[lineStart:start]) == "" {
+		start = lineStart
+		for start > 0 {
+			prevStart := strings.LastIndexByte(src[:start-1], '\n') + 1
+			if !strings.HasPrefix(strings.TrimSpace(src[prevStart:start-1]), "//") {
+				break
+			}
+			start = prevStart
+		}
+		if lineEnd := lineEndAfter(src, end); strings.HasPrefix(src[lineEnd:], "\r\n") {
+			end = lineEnd + 2
+		} else if lineEnd < len(src) && src[lineEnd] == '\n' {
+			end = lineEnd + 1
+		}
+	} else if member.comma < 0 && idx > 0 {
+		for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+			start--
+		}
+	} else {
+		for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+			end++
+		}
+	}
+
+	edits := []jsoncEdit{{start: start, end: end}}
+	if member.comma < 0 && idx > 0 {
+		prev := obj.members[idx-1]
+		edits = append([]jsoncEdit{{start: prev.comma, end: prev.comma + 1}}, edits...)
+	}
+	return edits
+}
+
+func applyJSONCEdits(src string, edits []jsoncEdit) (string, error) {
+	if len(edits) == 0 {
+		return "", fmt.Errorf("nothing to edit")
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out strings.Builder
+	offset := 0
+	for _, edit := range edits {
+		out.WriteString(src[offset:edit.start])
+		out.WriteString(edit.text)
+		offset = edit.end
+	}
+	out.WriteString(src[offset:])
+	result := out.String()
+	if _, err := parseJSONC(result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func renderJSONCValue(value any, indent, unit, newline string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if unit != "" {
+		encoder.SetIndent(indent, unit)
+	}
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	text := strings.TrimSuffix(buf.String(), "\n")
+	if unit != "" {
+		return strings.ReplaceAll(text, "\n", newline), nil
+	}
+	var out strings.Builder
+	inString := false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		out.WriteByte(ch)
+		switch {
+		case inString && ch == '\\':
+			i++
+			out.WriteByte(text[i])
+		case ch == '"':
+			inString = !inString
+		case !inString && (ch == ':' || ch == ','):
+			out.WriteByte(' ')
+		}
+	}
+	return out.String(), nil
+}
+
+func isSingleLineJSONC(src string, root *jsoncNode) bool {
+	return len(root.members) > 0 && !strings.Contains(src[root.start:root.end], "\n")
+}
+
+func trailingJSONCComment(src string, pos int) int {
+	end := pos
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	switch {
+	case strings.HasPrefix(src[end:], "//"):
+		lineEnd := strings.IndexByte(src[end:], '\n')
+		if lineEnd == -1 {
+			return len(src)
+		}
+		end += lineEnd
+		if src[end-1] == '\r' {
+			end--
+		}
+		return end
+	case strings.HasPrefix(src[end:], "/*"):
+		close := strings.Index(src[end+2:], "*/")
+		if close == -1 || strings.Contains(src[end:end+2+close], "\n") {
+			return pos
+		}
+		return end + 2 + close + 2
+	}
+	return pos
+}
+
+func detectJSONIndent(src string) string {
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || len(trimmed) == len(line) {
+			continue
+		}
+		return line[:len(line)-len(trimmed)]
+	}
+	return "  "
+}
+
+func detectJSONNewline(src string) string {
+	if strings.Contains(src, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+func lineIndent(src string, pos int) string {
+	lineStart := strings.LastIndexByte(src[:pos], '\n') + 1
+	line := src[lineStart:pos]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func lineEndAfter(src string, pos int) int {
+	rest := src[pos:]
+	lineEnd := strings.IndexByte(rest, '\n')
+	if lineEnd == -1 {
+		lineEnd = len(rest)
+	}
+	if lineEnd > 0 && rest[lineEnd-1] == '\r' {
+		lineEnd--
+	}
+	trimmed := strings.TrimSpace(rest[:lineEnd])
+	if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+		return pos + lineEnd
+	}
+	return pos
+}
+
+func onlyJSONCWhitespace(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func TestSetJSONCValue(t *testing.T) {
+	server := map[string]any{"command": "npx"}
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "empty document",
+			in:   "",
+			want: "{\n  \"mcpServers\": {\n    \"docs\": {\n      \"command\": \"npx\"\n    }\n  }\n}\n",
+		},
+		{
+			name: "keeps comments and indentation",
+			in:   "{\n\t// servers\n\t\"mcpServers\": {\n\t\t\"a\": {} // about a\n\t}\n}\n",
+			want: "{\n\t// servers\n\t\"mcpServers\": {\n\t\t\"a\": {}, // about a\n\t\t\"docs\": {\n\t\t\t\"command\": \"npx\"\n\t\t}\n\t}\n}\n",
+		},
+		{
+			name: "after a trailing comma",
+			in:   "{\n  \"mcpServers\": {\n    \"a\": {},\n  }\n}\n",
+			want: "{\n  \"mcpServers\": {\n    \"a\": {},\n    \"docs\": {\n      \"command\": \"npx\"\n    },\n  }\n}\n",
+		},
+		{
+			name: "replaces an existing value in place",
+			in:   "{\n  \"mcpServers\": {\n    \"docs\": {\"command\": \"old\"}, // pinned\n    \"b\": {}\n  }\n}\n",
+			want: "{\n  \"mcpServers\": {\n    \"docs\": {\n      \"command\": \"npx\"\n    }, // pinned\n    \"b\": {}\n  }\n}\n",
+		},
+		{
+			name: "single-line object",
+			in:   "{\"mcpServers\": {\"a\": {\"command\": \"x\"}}}\n",
+			want: "{\"mcpServers\": {\"a\": {\"command\": \"x\"}, \"docs\": {\"command\": \"npx\"}}}\n",
+		},
+		{
+			name: "single-line empty section",
+			in:   "{\"mcpServers\": {}}",
+			want: "{\"mcpServers\": {\"docs\": {\"command\": \"npx\"}}}",
+		},
+		{
+			name: "single-line document without the section",
+			in:   "{\"theme\": \"dark\"}\n",
+			want: "{\"theme\": \"dark\", \"mcpServers\": {\"docs\": {\"command\": \"npx\"}}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setJSONCValue(tt.in, []string{"mcpServers", "docs"}, server)
+			if err != nil {
+				t.Fatalf("setJSONCValue: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveJSONCValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "last member and its trailing comment",
+			in:   "{\n  \"mcpServers\": {\n    \"a\": {}, // about a\n    \"docs\": {} // about docs\n  }\n}\n",
+			want: "{\n  \"mcpServers\": {\n    \"a\": {} // about a\n  }\n}\n",
+		},
+		{
+			name: "leading and trailing comments of a multi-line member",
+			in:   "{\n  \"mcpServers\": {\n    // docs server\n    \"docs\": {\n      \"command\": \"y\"\n    }, // pinned\n    \"b\": {} // about b\n  }\n}\n",
+			want: "{\n  \"mcpServers\": {\n    \"b\": {} // about b\n  }\n}\n",
+		},
+		{
+			name: "only member with a trailing comment",
+			in:   "{\n  \"mcpServers\": {\n    \"docs\": {} // only\n  }\n}\n",
+			want: "{\n  \"mcpServers\": {}\n}\n",
+		},
+		{
+			name: "block comment on a single line",
+			in:   "{\"mcpServers\": {\"a\": {}, \"docs\": {\"command\": \"y\"} /* pinned */}}\n",
+			want: "{\"mcpServers\": {\"a\": {}}}\n",
+		},
+		{
+			name: "first member of a single-line object",
+			in:   "{\"mcpServers\": {\"docs\": {}, \"a\": {}}} // end\n",
+			want: "{\"mcpServers\": {\"a\": {}}} // end\n",
+		},
+		{
+			name: "line comment after an inline member",
+			in:   "{\"mcpServers\": {\"docs\": {}, // pinned\n  \"a\": {}}}\n",
+			want: "{\"mcpServers\": {\n  \"a\": {}}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed, err := removeJSONCValue(tt.in, []string{"mcpServers", "docs"})
+			if err != nil || !removed {
+				t.Fatalf("removeJSONCValue: removed=%v err=%v", removed, err)
+			}
+			if got != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONCKeepsCRLF(t *testing.T) {
+	crlf := func(text string) string { return strings.ReplaceAll(text, "\n", "\r\n") }
+	server := map[string]any{"command": "npx", "args": []string{"-y"}}
+	tests := []struct {
+		name string
+		in   string
+		edit func(string) (string, error)
+		want string
+	}{
+		{
+			name: "insert after the last member",
+			in:   "{\n  \"mcpServers\": {\n    \"a\": {}\n  }\n}\n",
+			edit: func(src string) (string, error) { return setJSONCValue(src, []string{"mcpServers", "docs"}, server) },
+			want: "{\n  \"mcpServers\": {\n    \"a\": {},\n    \"docs\": {\n      \"args\": [\n        \"-y\"\n      ],\n      \"command\": \"npx\"\n    }\n  }\n}\n",
+		},
+		{
+			name: "insert into an empty section",
+			in:   "{\n  \"mcpServers\": {}\n}\n",
+			edit: func(src string) (string, error) { return setJSONCValue(src, []string{"mcpServers", "docs"}, server) },
+			want: "{\n  \"mcpServers\": {\n    \"docs\": {\n      \"args\": [\n        \"-y\"\n      ],\n      \"command\": \"npx\"\n    }\n  }\n}\n",
+		},
+		{
+			name: "replace in place",
+			in:   "{\n  \"mcpServers\": {\n    \"docs\": {}, // pinned\n    \"b\": {}\n  }\n}\n",
+			edit: func(src string) (string, error) { return setJSONCValue(src, []string{"mcpServers", "docs"}, server) },
+			want: "{\n  \"mcpServers\": {\n    \"docs\": {\n      \"args\": [\n        \"-y\"\n      ],\n      \"command\": \"npx\"\n    }, // pinned\n    \"b\": {}\n  }\n}\n",
+		},
+		{
+			name: "remove a member line",
+			in:   "{\n  \"mcpServers\": {\n    // docs server\n    \"docs\": {}, // pinned\n    \"b\": {}\n  }\n}\n",
+			edit: func(src string) (string, error) {
+				out, _, err := removeJSONCValue(src, []string{"mcpServers", "docs"})
+				return out, err
+			},
+			want: "{\n  \"mcpServers\": {\n    \"b\": {}\n  }\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.edit(crlf(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := crlf(tt.want); got != want {
+				t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+			}
+		})
+	}
+}
+
+func TestJSONAdaptersRoundTrip(t *testing.T) {
+	defs := []Definition{
+		{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"-y", "docs"}, Env: map[string]string{"MODE": "fast", "TOKEN": SecretReference("TOKEN")}},
+		{Name: "remote", Transport: "http", URL: "https://example.com/mcp?a=1&b=<2>", Headers: map[string]string{"Authorization": "Bearer " + SecretReference("TOKEN")}},
+	}
+	for _, client := range SupportedClients() {
+		adapter, err := AdapterFor(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := adapter.(jsonAdapter); !ok || !adapter.SupportsScope(installer.ScopeProject) {
+			continue
+		}
+		t.Run(string(client), func(t *testing.T) {
+			cwd := t.TempDir()
+			for _, def := range defs {
+				if _, err := adapter.Install(def, installer.ScopeProject, cwd, false); err != nil {
+					t.Fatalf("Install %s: %v", def.Name, err)
+				}
+			}
+			for _, def := range defs {
+				got, ok, err := adapter.Read(def.Name, installer.ScopeProject, cwd)
+				if err != nil || !ok {
+					t.Fatalf("Read %s: ok=%v err=%v", def.Name, ok, err)
+				}
+				want, err := normalizeDefinition(def, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("read back %+v, want %+v", got, want)
+				}
+			}
+			if _, err := adapter.Uninstall("docs", installer.ScopeProject, cwd, false); err != nil {
+				t.Fatalf("Uninstall: %v", err)
+			}
+			entries, _, err := adapter.List(installer.ScopeProject, cwd)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Name != "remote" {
+				t.Fatalf("List after uninstall = %+v", entries)
+			}
+		})
+	}
+}
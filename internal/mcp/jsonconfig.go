@@ -3,11 +3,12 @@ package mcp
 import (
 	"encoding/json"
 	"os"
-	"path/filepath"
+
+	"mcp-skill-manager/internal/safefile"
 )
 
 func loadJSONConfig(path string) (map[string]any, error) {
-	data, err := os.ReadFile(path)
+	data, err := safefile.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return map[string]any{}, nil
@@ -19,7 +20,7 @@ func loadJSONConfig(path string) (map[string]any, error) {
 		return map[string]any{}, nil
 	}
 
-	clean := stripJSONComments(data)
+	clean := stripJSONTrailingCommas(stripJSONComments(data))
 
 	var config map[string]any
 	if err := json.Unmarshal(clean, &config); err != nil {
@@ -32,15 +33,19 @@ func loadJSONConfig(path string) (map[string]any, error) {
 	return config, nil
 }
 
-func writeJSONConfig(path string, config map[string]any) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(config, "", "  ")
+func readJSONSource(path string) (string, error) {
+	data, err := safefile.ReadFile(path)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
 	}
-	return os.WriteFile(path, data, 0o644)
+	return string(data), nil
+}
+
+func writeJSONSource(path, src string) error {
+	return safefile.WriteFile(path, []byte(src), 0o644)
 }
 
 func ensureMap(parent map[string]any, key string) map[string]any {
@@ -124,3 +129,37 @@ func stripJSONComments(data []byte) []byte {
 
 	return out
 }
+
+func stripJSONTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	escape := false
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		if inString {
+			out = append(out, ch)
+			if escape {
+				escape = false
+			} else if ch == '\\' {
+				escape = true
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+		if ch == '"' {
+			inString = true
+		}
+		if ch == ',' {
+			next := i + 1
+			for next < len(data) && (data[next] == ' ' || data[next] == '\t' || data[next] == '\r' || data[next] == '\n') {
+				next++
+			}
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				continue
+			}
+		}
+		out = append(out, ch)
+	}
+	return out
+}
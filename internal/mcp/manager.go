@@ -1,17 +1,16 @@
 package mcp
 
 import (
-	"fmt"
-
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
 )
 
 type Installed struct {
-	Name      string
-	Client    installer.Tool
-	Scope     string
-	Path      string
-	Transport string
+	Name      string         `json:"name"`
+	Client    installer.Tool `json:"client"`
+	Scope     string         `json:"scope"`
+	Path      string         `json:"path"`
+	Transport string         `json:"transport,omitempty"`
 }
 
 func Install(def Definition, scope, cwd string, clients []installer.Tool, force bool) ([]Installed, error) {
@@ -101,47 +100,59 @@ func List(scopes []string, cwd string, clients []installer.Tool) ([]Installed, e
 	return results, nil
 }
 
+func Read(client installer.Tool, name, scope, cwd string) (Definition, bool, error) {
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return Definition{}, false, err
+	}
+	return adapter.Read(name, scope, cwd)
+}
+
 func installForClient(client installer.Tool, def Definition, scope, cwd string, force bool) (string, error) {
-	switch client {
-	case installer.ToolClaude:
-		return InstallClaude(def, scope, cwd, force)
-	case installer.ToolCodex:
-		return InstallCodex(def, scope, cwd, force)
-	case installer.ToolGemini:
-		return InstallGemini(def, scope, cwd, force)
-	case installer.ToolOpenCode:
-		return InstallOpenCode(def, scope, cwd, force)
-	default:
-		return "", fmt.Errorf("unsupported client: %s", client)
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return "", err
 	}
+	var path string
+	err = withConfigLock(adapter, scope, cwd, "install "+def.Name, func() error {
+		var installErr error
+		path, installErr = adapter.Install(def, scope, cwd, force)
+		return installErr
+	})
+	return path, err
 }
 
 func uninstallForClient(client installer.Tool, name, scope, cwd string, force bool) (string, error) {
-	switch client {
-	case installer.ToolClaude:
-		return UninstallClaude(name, scope, cwd, force)
-	case installer.ToolCodex:
-		return UninstallCodex(name, scope, cwd, force)
-	case installer.ToolGemini:
-		return UninstallGemini(name, scope, cwd, force)
-	case installer.ToolOpenCode:
-		return UninstallOpenCode(name, scope, cwd, force)
-	default:
-		return "", fmt.Errorf("unsupported client: %s", client)
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return "", err
 	}
+	var path string
+	err = withConfigLock(adapter, scope, cwd, "uninstall "+name, func() error {
+		var uninstallErr error
+		path, uninstallErr = adapter.Uninstall(name, scope, cwd, force)
+		return uninstallErr
+	})
+	return path, err
+}
+
+func withConfigLock(adapter ClientAdapter, scope, cwd, operation string, fn func() error) error {
+	path, err := adapter.ConfigPath(scope, cwd)
+	if err != nil {
+		return err
+	}
+	return safefile.WithLock(path, func() error {
+		return withBackup(adapter.Client(), scope, path, operation, fn)
+	})
 }
 
 func listForClient(client installer.Tool, scope, cwd string) ([]Entry, string, error) {
-	switch client {
-	case installer.ToolClaude:
-		return ListClaude(scope, cwd)
-	case installer.ToolCodex:
-		return ListCodex(scope, cwd)
-	case installer.ToolGemini:
-		return ListGemini(scope, cwd)
-	case installer.ToolOpenCode:
-		return ListOpenCode(scope, cwd)
-	default:
-		return nil, "", fmt.Errorf("unsupported client: %s", client)
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return nil, "", err
+	}
+	if !adapter.SupportsScope(scope) {
+		return nil, "", nil
 	}
+	return adapter.List(scope, cwd)
 }
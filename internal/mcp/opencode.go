@@ -1,90 +1,30 @@
 package mcp
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 
 	"mcp-skill-manager/internal/installer"
 )
 
-func OpenCodeConfigPath(scope, cwd string) (string, error) {
-	switch scope {
-	case installer.ScopeUser:
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		return filepath.Join(home, ".config", "opencode", "opencode.json"), nil
-	case installer.ScopeProject:
-		if cwd == "" {
-			return "", fmt.Errorf("project scope requires working directory")
-		}
-		return filepath.Join(cwd, ".opencode", "opencode.json"), nil
-	default:
-		return "", fmt.Errorf("invalid scope: %s", scope)
+func newOpenCodeAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolOpenCode,
+		userPath: func(home string) string {
+			return filepath.Join(home, ".config", "opencode", "opencode.json")
+		},
+		projectPath: func(cwd string) string {
+			return filepath.Join(cwd, ".opencode", "opencode.json")
+		},
+		section:    "mcp",
+		toServer:   toOpenCodeServer,
+		fromServer: fromOpenCodeServer,
+		references: openCodeReferences,
+		defaults: map[string]any{
+			"$schema": "https://opencode.ai/config.json",
+		},
 	}
 }
 
-func InstallOpenCode(def Definition, scope, cwd string, force bool) (string, error) {
-	path, err := OpenCodeConfigPath(scope, cwd)
-	if err != nil {
-		return "", err
-	}
-	config, err := loadJSONConfig(path)
-	if err != nil {
-		return "", err
-	}
-	mcpSection := ensureMap(config, "mcp")
-	if _, exists := mcpSection[def.Name]; exists && !force {
-		return "", fmt.Errorf("server already exists: %s", def.Name)
-	}
-	mcpSection[def.Name] = toOpenCodeServer(def)
-	if _, ok := config["$schema"]; !ok {
-		config["$schema"] = "https://opencode.ai/config.json"
-	}
-	if err := writeJSONConfig(path, config); err != nil {
-		return "", err
-	}
-	return path, nil
-}
-
-func UninstallOpenCode(name, scope, cwd string, force bool) (string, error) {
-	path, err := OpenCodeConfigPath(scope, cwd)
-	if err != nil {
-		return "", err
-	}
-	config, err := loadJSONConfig(path)
-	if err != nil {
-		return "", err
-	}
-	mcpSection := ensureMap(config, "mcp")
-	if _, exists := mcpSection[name]; !exists {
-		if force {
-			return path, nil
-		}
-		return "", fmt.Errorf("server not found: %s", name)
-	}
-	delete(mcpSection, name)
-	if err := writeJSONConfig(path, config); err != nil {
-		return "", err
-	}
-	return path, nil
-}
-
-func ListOpenCode(scope, cwd string) ([]Entry, string, error) {
-	path, err := OpenCodeConfigPath(scope, cwd)
-	if err != nil {
-		return nil, "", err
-	}
-	config, err := loadJSONConfig(path)
-	if err != nil {
-		return nil, "", err
-	}
-	servers := ensureMap(config, "mcp")
-	return extractEntries(servers), path, nil
-}
-
 func toOpenCodeServer(def Definition) map[string]any {
 	if def.Transport == "http" {
 		server := map[string]any{
@@ -107,3 +47,23 @@ func toOpenCodeServer(def Definition) map[string]any {
 	}
 	return server
 }
+
+func fromOpenCodeServer(name string, server map[string]any) (Definition, error) {
+	def := Definition{
+		Name:      name,
+		Transport: detectTransport(server),
+		URL:       stringValue(server["url"]),
+		Headers:   stringMap(server["headers"]),
+	}
+	if command := stringSlice(server["command"]); len(command) > 0 {
+		def.Command = command[0]
+		def.Args = command[1:]
+	} else {
+		def.Command = stringValue(server["command"])
+	}
+	def.Env = stringMap(server["env"])
+	if def.Env == nil {
+		def.Env = stringMap(server["environment"])
+	}
+	return normalizeDefinition(def, "")
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func newRooAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolRooCode,
+		userPath: func(home string) string {
+			return filepath.Join(vscodeUserDir(home), "globalStorage", "rooveterinaryinc.roo-cline", "settings", "mcp_settings.json")
+		},
+		projectPath: func(cwd string) string {
+			return filepath.Join(cwd, ".roo", "mcp.json")
+		},
+		section:    "mcpServers",
+		toServer:   toRooServer,
+		fromServer: fromClaudeServer,
+	}
+}
+
+func newKiloCodeAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolKiloCode,
+		userPath: func(home string) string {
+			return filepath.Join(vscodeUserDir(home), "globalStorage", "kilocode.kilo-code", "settings", "mcp_settings.json")
+		},
+		projectPath: func(cwd string) string {
+			return filepath.Join(cwd, ".kilocode", "mcp.json")
+		},
+		section:    "mcpServers",
+		toServer:   toRooServer,
+		fromServer: fromClaudeServer,
+	}
+}
+
+func toRooServer(def Definition) map[string]any {
+	server := toClaudeServer(def)
+	if def.Transport == "http" {
+		server["type"] = "streamable-http"
+	}
+	return server
+}
+
+func vscodeUserDir(home string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "Code", "User")
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "Code", "User")
+		}
+		return filepath.Join(home, "AppData", "Roaming", "Code", "User")
+	default:
+		if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+			return filepath.Join(configHome, "Code", "User")
+		}
+		return filepath.Join(home, ".config", "Code", "User")
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"regexp"
+	"sort"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+var secretReferencePattern = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+type referenceStyle struct {
+	toClient   func(name string) string
+	fromClient *regexp.Regexp
+}
+
+var (
+	dollarReferences = referenceStyle{
+		toClient:   func(name string) string { return "${" + name + "}" },
+		fromClient: regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`),
+	}
+	openCodeReferences = referenceStyle{
+		toClient:   func(name string) string { return "{env:" + name + "}" },
+		fromClient: regexp.MustCompile(`\$?\{env:([A-Za-z_][A-Za-z0-9_]*)\}`),
+	}
+)
+
+func SecretReference(name string) string {
+	return "${env:" + name + "}"
+}
+
+func SecretReferences(def Definition) []string {
+	seen := map[string]bool{}
+	mapDefinitionValues(def, func(value string) string {
+		for _, match := range secretReferencePattern.FindAllStringSubmatch(value, -1) {
+			seen[match[1]] = true
+		}
+		return value
+	})
+	return sortedKeys(seen)
+}
+
+func ResolveSecretReferences(def Definition, lookup func(name string) (string, bool)) (Definition, []string) {
+	missing := map[string]bool{}
+	resolved := mapDefinitionValues(def, func(value string) string {
+		return secretReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+			name := secretReferencePattern.FindStringSubmatch(match)[1]
+			if secret, ok := lookup(name); ok {
+				return secret
+			}
+			missing[name] = true
+			return match
+		})
+	})
+	return resolved, sortedKeys(missing)
+}
+
+func UnsupportedReferences(client installer.Tool, def Definition) []string {
+	adapter, err := AdapterFor(client)
+	if err != nil {
+		return nil
+	}
+	if checker, ok := adapter.(interface {
+		unsupportedReferences(def Definition) []string
+	}); ok {
+		return checker.unsupportedReferences(def)
+	}
+	return nil
+}
+
+func (s referenceStyle) write(def Definition) Definition {
+	if s.toClient == nil {
+		return def
+	}
+	return mapDefinitionValues(def, func(value string) string {
+		return secretReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+			return s.toClient(secretReferencePattern.FindStringSubmatch(match)[1])
+		})
+	})
+}
+
+func (s referenceStyle) read(def Definition) Definition {
+	if s.fromClient == nil {
+		return def
+	}
+	return mapDefinitionValues(def, func(value string) string {
+		return s.fromClient.ReplaceAllStringFunc(value, func(match string) string {
+			if secretReferencePattern.MatchString(match) {
+				return match
+			}
+			return SecretReference(s.fromClient.FindStringSubmatch(match)[1])
+		})
+	})
+}
+
+func forwardedEnv(env map[string]string) (map[string]string, []string) {
+	var keys []string
+	rest := map[string]string{}
+	for key, value := range env {
+		if value == SecretReference(key) {
+			keys = append(keys, key)
+			continue
+		}
+		rest[key] = value
+	}
+	sort.Strings(keys)
+	if len(rest) == 0 {
+		rest = nil
+	}
+	return rest, keys
+}
+
+func referencesIn(values ...string) []string {
+	seen := map[string]bool{}
+	for _, value := range values {
+		for _, match := range secretReferencePattern.FindAllStringSubmatch(value, -1) {
+			seen[match[1]] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+func mapDefinitionValues(def Definition, fn func(string) string) Definition {
+	def.URL = fn(def.URL)
+	def.Command = fn(def.Command)
+	if def.Args != nil {
+		args := make([]string, len(def.Args))
+		for idx, arg := range def.Args {
+			args[idx] = fn(arg)
+		}
+		def.Args = args
+	}
+	def.Env = mapStringValues(def.Env, fn)
+	def.Headers = mapStringValues(def.Headers, fn)
+	return def
+}
+
+func mapStringValues(values map[string]string, fn func(string) string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	mapped := make(map[string]string, len(values))
+	for key, value := range values {
+		mapped[key] = fn(value)
+	}
+	return mapped
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
 )
 
 func LocalDefinitionPath(name string) (string, error) {
@@ -29,21 +30,21 @@ func SaveLocalDefinition(def Definition) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return "", err
-	}
 	data, err := json.MarshalIndent(definitionFile{
-		Transport: def.Transport,
-		URL:       def.URL,
-		Command:   def.Command,
-		Args:      def.Args,
-		Env:       def.Env,
-		Headers:   def.Headers,
+		Transport:         def.Transport,
+		URL:               def.URL,
+		Command:           def.Command,
+		Args:              def.Args,
+		Env:               def.Env,
+		Headers:           def.Headers,
+		StartupTimeoutSec: def.StartupTimeoutSec,
+		ToolTimeoutSec:    def.ToolTimeoutSec,
+		Disabled:          def.Disabled,
 	}, "", "  ")
 	if err != nil {
 		return "", err
 	}
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := safefile.WriteFile(path, data, 0o644); err != nil {
 		return "", err
 	}
 	return path, nil
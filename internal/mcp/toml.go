@@ -1,135 +1,920 @@
 package mcp
 
 import (
-	"bufio"
 	"fmt"
+	"math"
 	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"mcp-skill-manager/internal/safefile"
+)
+
+type tomlStatementKind int
+
+const (
+	tomlTrivia tomlStatementKind = iota
+	tomlTable
+	tomlArrayTable
+	tomlKeyValue
 )
 
-type tomlBlock struct {
-	kind  string
-	name  string
-	lines []string
+type tomlStatement struct {
+	kind  tomlStatementKind
+	path  []string
+	key   []string
+	value any
+	raw   string
+	start int
+	end   int
+}
+
+type tomlDocument struct {
+	src   string
+	stmts []tomlStatement
+}
+
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
 }
 
-func parseTomlBlocks(path string) ([]tomlBlock, error) {
-	file, err := os.Open(path)
+func loadTomlDocument(path string) (*tomlDocument, error) {
+	data, err := safefile.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []tomlBlock{}, nil
+			return &tomlDocument{}, nil
 		}
 		return nil, err
 	}
-	defer file.Close()
+	doc, err := parseTomlDocument(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
 
-	var blocks []tomlBlock
-	current := tomlBlock{kind: "other"}
+func (d *tomlDocument) save(path string) error {
+	return safefile.WriteFile(path, []byte(d.src), 0o644)
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		table := parseTomlTable(line)
-		if table != "" {
-			if current.kind == "mcp" && isMcpSubtable(current.name, table) {
-				current.lines = append(current.lines, line)
-				continue
+func parseTomlDocument(src string) (*tomlDocument, error) {
+	p := &tomlParser{src: src, line: 1}
+	doc := &tomlDocument{src: src}
+	var table []string
+	for p.pos < len(src) {
+		start := p.pos
+		p.skipSpaces()
+		stmt := tomlStatement{kind: tomlTrivia, start: start}
+		switch {
+		case p.pos >= len(src), p.peek() == '#', p.peek() == '\n', p.peek() == '\r':
+		case p.peek() == '[':
+			stmt.kind = tomlTable
+			p.pos++
+			if p.peek() == '[' {
+				stmt.kind = tomlArrayTable
+				p.pos++
+			}
+			p.skipSpaces()
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpaces()
+			closing := "]"
+			if stmt.kind == tomlArrayTable {
+				closing = "]]"
 			}
+			if !strings.HasPrefix(src[p.pos:], closing) {
+				return nil, p.errorf("expected %s", closing)
+			}
+			p.pos += len(closing)
+			table = key
+			stmt.path = key
+		default:
+			stmt.kind = tomlKeyValue
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpaces()
+			if p.peek() != '=' {
+				return nil, p.errorf("expected = after key")
+			}
+			p.pos++
+			p.skipSpaces()
+			valueStart := p.pos
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			stmt.key = key
+			stmt.path = append(append([]string{}, table...), key...)
+			stmt.value = value
+			stmt.raw = src[valueStart:p.pos]
+		}
+		if err := p.finishLine(); err != nil {
+			return nil, err
+		}
+		stmt.end = p.pos
+		doc.stmts = append(doc.stmts, stmt)
+	}
+	if err := doc.checkDuplicates(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
 
-			if len(current.lines) > 0 {
-				blocks = append(blocks, current)
+func (d *tomlDocument) checkDuplicates() error {
+	arrays := map[string]int{}
+	defined := map[string]bool{}
+	resolve := func(path []string) string {
+		var out strings.Builder
+		for i, part := range path {
+			if i > 0 {
+				out.WriteByte(0)
+			}
+			out.WriteString(part)
+			if count, ok := arrays[strings.Join(path[:i+1], "\x00")]; ok {
+				fmt.Fprintf(&out, "[%d]", count)
+			}
+		}
+		return out.String()
+	}
+	for _, stmt := range d.stmts {
+		line := strings.Count(d.src[:stmt.start], "\n") + 1
+		switch stmt.kind {
+		case tomlArrayTable:
+			arrays[strings.Join(stmt.path, "\x00")]++
+		case tomlTable:
+			key := resolve(stmt.path)
+			if defined["["+key] {
+				return fmt.Errorf("invalid TOML at line %d: table %s is defined twice", line, formatTomlPath(stmt.path...))
 			}
-			if isMcpTable(table) {
-				name := strings.TrimPrefix(table, "mcp_servers.")
-				current = tomlBlock{kind: "mcp", name: name, lines: []string{line}}
-			} else {
-				current = tomlBlock{kind: "other", lines: []string{line}}
+			defined["["+key] = true
+		case tomlKeyValue:
+			key := resolve(stmt.path)
+			if defined[key] {
+				return fmt.Errorf("invalid TOML at line %d: duplicate key %s", line, formatTomlPath(stmt.path...))
+			}
+			defined[key] = true
+		}
+	}
+	return nil
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid TOML at line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipNewline() bool {
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if p.peek() == '\n' {
+		p.pos++
+	} else {
+		return false
+	}
+	p.line++
+	return true
+}
+
+func (p *tomlParser) skipWhitespaceAndComments() {
+	for {
+		p.skipSpaces()
+		p.skipComment()
+		if !p.skipNewline() {
+			return
+		}
+	}
+}
+
+func (p *tomlParser) finishLine() error {
+	p.skipSpaces()
+	p.skipComment()
+	if p.pos >= len(p.src) || p.skipNewline() {
+		return nil
+	}
+	return p.errorf("unexpected %q", p.peek())
+}
+
+func (p *tomlParser) parseKey() ([]string, error) {
+	var key []string
+	for {
+		part, err := p.parseSimpleKey()
+		if err != nil {
+			return nil, err
+		}
+		key = append(key, part)
+		p.skipSpaces()
+		if p.peek() != '.' {
+			return key, nil
+		}
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+func (p *tomlParser) parseSimpleKey() (string, error) {
+	switch p.peek() {
+	case '"':
+		return p.parseBasicString()
+	case '\'':
+		return p.parseLiteralString()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && isTomlBareKeyChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected key")
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		return p.parseMultilineBasicString()
+	case strings.HasPrefix(p.src[p.pos:], `'''`):
+		return p.parseMultilineLiteralString()
+	case p.peek() == '"':
+		return p.parseBasicString()
+	case p.peek() == '\'':
+		return p.parseLiteralString()
+	case p.peek() == '[':
+		return p.parseArray()
+	case p.peek() == '{':
+		return p.parseInlineTable()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(",]}#\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	token := strings.TrimRight(p.src[start:p.pos], " \t")
+	p.pos = start + len(token)
+	if token == "" {
+		return nil, p.errorf("expected value")
+	}
+	return parseTomlScalar(token), nil
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var out strings.Builder
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		switch ch {
+		case '"':
+			p.pos++
+			return out.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			if err := p.parseEscape(&out); err != nil {
+				return "", err
+			}
+		default:
+			out.WriteByte(ch)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) parseMultilineBasicString() (string, error) {
+	p.pos += 3
+	p.skipNewline()
+	var out strings.Builder
+	for p.pos < len(p.src) {
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			p.pos += 3
+			for i := 0; i < 2 && p.peek() == '"'; i++ {
+				out.WriteByte('"')
+				p.pos++
+			}
+			return out.String(), nil
+		}
+		ch := p.src[p.pos]
+		if ch == '\\' {
+			rest := strings.TrimLeft(p.src[p.pos+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				p.pos = len(p.src) - len(rest)
+				for {
+					p.skipSpaces()
+					if !p.skipNewline() {
+						break
+					}
+				}
+				continue
+			}
+			if err := p.parseEscape(&out); err != nil {
+				return "", err
 			}
 			continue
 		}
+		if ch == '\n' {
+			p.line++
+		}
+		out.WriteByte(ch)
+		p.pos++
+	}
+	return "", p.errorf("unterminated multi-line string")
+}
 
-		current.lines = append(current.lines, line)
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end == -1 || p.src[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
 	}
+	value := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return value, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+func (p *tomlParser) parseMultilineLiteralString() (string, error) {
+	p.pos += 3
+	p.skipNewline()
+	end := strings.Index(p.src[p.pos:], `'''`)
+	if end == -1 {
+		return "", p.errorf("unterminated multi-line string")
+	}
+	for end+3 < len(p.src[p.pos:]) && p.src[p.pos+end+3] == '\'' {
+		end++
+	}
+	value := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + 3
+	return value, nil
+}
+
+func (p *tomlParser) parseEscape(out *strings.Builder) error {
+	p.pos++
+	if p.pos >= len(p.src) {
+		return p.errorf("unterminated escape")
+	}
+	ch := p.src[p.pos]
+	p.pos++
+	switch ch {
+	case 'b':
+		out.WriteByte('\b')
+	case 't':
+		out.WriteByte('\t')
+	case 'n':
+		out.WriteByte('\n')
+	case 'f':
+		out.WriteByte('\f')
+	case 'r':
+		out.WriteByte('\r')
+	case 'e':
+		out.WriteByte(0x1b)
+	case '"', '\\':
+		out.WriteByte(ch)
+	case 'u', 'U':
+		size := 4
+		if ch == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+		out.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return p.errorf("invalid escape \\%c", ch)
+	}
+	return nil
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++
+	items := []any{}
+	for {
+		p.skipWhitespaceAndComments()
+		if p.peek() == ']' {
+			p.pos++
+			return items, nil
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		p.skipWhitespaceAndComments()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
 	}
-	if len(current.lines) > 0 {
-		blocks = append(blocks, current)
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.pos++
+	table := map[string]any{}
+	for {
+		p.skipWhitespaceAndComments()
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated inline table")
+		}
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peek() != '=' {
+			return nil, p.errorf("expected = in inline table")
+		}
+		p.pos++
+		p.skipSpaces()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		setTomlPath(table, key, value)
+		p.skipWhitespaceAndComments()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
 	}
-	return blocks, nil
 }
 
-func writeTomlBlocks(path string, blocks []tomlBlock) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+func parseTomlScalar(token string) any {
+	switch token {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "inf", "+inf":
+		return math.Inf(1)
+	case "-inf":
+		return math.Inf(-1)
+	case "nan", "+nan", "-nan":
+		return math.NaN()
+	}
+	clean := strings.ReplaceAll(token, "_", "")
+	if value, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return value
+	}
+	if value, err := strconv.ParseFloat(clean, 64); err == nil {
+		return value
+	}
+	return token
+}
+
+func isTomlBareKeyChar(ch byte) bool {
+	return ch == '_' || ch == '-' || (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func tomlChild(table map[string]any, part string) map[string]any {
+	switch value := table[part].(type) {
+	case map[string]any:
+		return value
+	case []any:
+		if len(value) > 0 {
+			if last, ok := value[len(value)-1].(map[string]any); ok {
+				return last
+			}
+		}
+	}
+	child := map[string]any{}
+	table[part] = child
+	return child
+}
+
+func appendTomlTable(table map[string]any, path []string) {
+	for _, part := range path[:len(path)-1] {
+		table = tomlChild(table, part)
+	}
+	last := path[len(path)-1]
+	items, _ := table[last].([]any)
+	table[last] = append(items, map[string]any{})
+}
+
+func setTomlPath(table map[string]any, path []string, value any) {
+	for _, part := range path[:len(path)-1] {
+		table = tomlChild(table, part)
+	}
+	last := path[len(path)-1]
+	if existing, ok := table[last].(map[string]any); ok {
+		if incoming, ok := value.(map[string]any); ok {
+			for key, item := range incoming {
+				existing[key] = item
+			}
+			return
+		}
+	}
+	table[last] = value
+}
+
+func hasTomlPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *tomlDocument) tables(prefix []string) map[string]map[string]any {
+	result := map[string]map[string]any{}
+	ensure := func(name string) map[string]any {
+		if result[name] == nil {
+			result[name] = map[string]any{}
+		}
+		return result[name]
+	}
+	for _, stmt := range d.stmts {
+		switch {
+		case stmt.kind == tomlTable && hasTomlPrefix(stmt.path, prefix) && len(stmt.path) > len(prefix):
+			name := stmt.path[len(prefix)]
+			table := ensure(name)
+			if rest := stmt.path[len(prefix)+1:]; len(rest) > 0 {
+				setTomlPath(table, rest, map[string]any{})
+			}
+		case stmt.kind == tomlArrayTable && hasTomlPrefix(stmt.path, prefix) && len(stmt.path) > len(prefix)+1:
+			appendTomlTable(ensure(stmt.path[len(prefix)]), stmt.path[len(prefix)+1:])
+		case stmt.kind == tomlKeyValue && hasTomlPrefix(stmt.path, prefix) && len(stmt.path) > len(prefix)+1:
+			setTomlPath(ensure(stmt.path[len(prefix)]), stmt.path[len(prefix)+1:], stmt.value)
+		case stmt.kind == tomlKeyValue && hasTomlPrefix(stmt.path, prefix) && len(stmt.path) == len(prefix)+1:
+			if value, ok := stmt.value.(map[string]any); ok {
+				table := ensure(stmt.path[len(prefix)])
+				for key, item := range value {
+					table[key] = item
+				}
+			}
+		case stmt.kind == tomlKeyValue && len(stmt.path) == len(prefix) && hasTomlPrefix(stmt.path, prefix):
+			if value, ok := stmt.value.(map[string]any); ok {
+				for name, item := range value {
+					if fields, ok := item.(map[string]any); ok {
+						table := ensure(name)
+						for key, field := range fields {
+							table[key] = field
+						}
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (d *tomlDocument) ownedRanges(path []string) ([][2]int, error) {
+	var ranges [][2]int
+	for i := 0; i < len(d.stmts); i++ {
+		stmt := d.stmts[i]
+		switch {
+		case (stmt.kind == tomlTable || stmt.kind == tomlArrayTable) && hasTomlPrefix(stmt.path, path):
+			start := i
+			for start > 0 && d.stmts[start-1].kind == tomlTrivia && strings.HasPrefix(strings.TrimSpace(d.src[d.stmts[start-1].start:d.stmts[start-1].end]), "#") {
+				start--
+			}
+			end := i + 1
+			last := i
+			for end < len(d.stmts) && d.stmts[end].kind != tomlTable && d.stmts[end].kind != tomlArrayTable {
+				if d.stmts[end].kind == tomlKeyValue {
+					last = end
+				}
+				end++
+			}
+			ranges = append(ranges, [2]int{start, last + 1})
+			i = last
+		case stmt.kind == tomlKeyValue && hasTomlPrefix(stmt.path, path):
+			ranges = append(ranges, [2]int{i, i + 1})
+		case stmt.kind == tomlKeyValue && hasTomlPrefix(path, stmt.path):
+			if lookupTomlPath(stmt.value, path[len(stmt.path):]) {
+				return nil, fmt.Errorf("cannot edit %s inside inline table %s", formatTomlPath(path...), formatTomlPath(stmt.key...))
+			}
+		}
+	}
+	return ranges, nil
+}
+
+func (d *tomlDocument) replace(path []string, block string) error {
+	ranges, err := d.ownedRanges(path)
+	if err != nil {
 		return err
 	}
-	var lines []string
-	for _, block := range blocks {
-		lines = append(lines, block.lines...)
+	insertAt := -1
+	if len(ranges) > 0 && d.isTableRange(ranges[0]) {
+		insertAt = d.stmts[ranges[0][0]].start
+	}
+	var out strings.Builder
+	offset := 0
+	for _, r := range ranges {
+		start := d.stmts[r[0]].start
+		end := d.stmts[r[1]-1].end
+		out.WriteString(d.src[offset:start])
+		switch {
+		case start == insertAt && block != "":
+			out.WriteString(d.src[start:d.tableStart(r)])
+			out.WriteString(block)
+			if rest := d.src[end:]; rest != "" && !startsWithTomlBlankLine(rest) {
+				out.WriteString("\n")
+			}
+		case endsWithTomlBlankLine(out.String()) && startsWithTomlBlankLine(d.src[end:]):
+			end += strings.Index(d.src[end:], "\n") + 1
+		}
+		offset = end
+	}
+	out.WriteString(d.src[offset:])
+	src := out.String()
+	if block != "" && insertAt == -1 {
+		src = strings.TrimRight(src, "\n")
+		if src != "" {
+			src += "\n\n"
+		}
+		src += block
 	}
-	data := strings.Join(lines, "\n")
-	if len(data) > 0 && !strings.HasSuffix(data, "\n") {
-		data += "\n"
+	parsed, err := parseTomlDocument(src)
+	if err != nil {
+		return err
 	}
-	return os.WriteFile(path, []byte(data), 0o644)
+	*d = *parsed
+	return nil
 }
 
-func parseTomlTable(line string) string {
-	trimmed := strings.TrimSpace(line)
-	if !strings.HasPrefix(trimmed, "[") {
-		return ""
+func (d *tomlDocument) isTableRange(r [2]int) bool {
+	for i := r[0]; i < r[1]; i++ {
+		if d.stmts[i].kind == tomlTable || d.stmts[i].kind == tomlArrayTable {
+			return true
+		}
 	}
-	end := strings.Index(trimmed, "]")
-	if end == -1 {
-		return ""
+	return false
+}
+
+func (d *tomlDocument) tableStart(r [2]int) int {
+	for i := r[0]; i < r[1]; i++ {
+		if d.stmts[i].kind == tomlTable || d.stmts[i].kind == tomlArrayTable {
+			return d.stmts[i].start
+		}
 	}
-	table := strings.TrimSpace(trimmed[1:end])
-	return table
+	return d.stmts[r[0]].start
 }
 
-func isMcpTable(table string) bool {
-	return strings.HasPrefix(table, "mcp_servers.")
+func (d *tomlDocument) arrayTablePaths(path []string) [][]string {
+	var paths [][]string
+	for _, stmt := range d.stmts {
+		if stmt.kind == tomlArrayTable && hasTomlPrefix(stmt.path, path) && len(stmt.path) > len(path) {
+			paths = append(paths, stmt.path)
+		}
+	}
+	return paths
 }
 
-func isMcpSubtable(name, table string) bool {
-	prefix := "mcp_servers." + name + "."
-	return strings.HasPrefix(table, prefix)
+func insideTomlArray(path []string, arrays [][]string) bool {
+	for _, array := range arrays {
+		if hasTomlPrefix(path, array) {
+			return true
+		}
+	}
+	return false
 }
 
-func formatTomlEntry(def Definition) []string {
-	lines := []string{fmt.Sprintf("[mcp_servers.%s]", def.Name)}
-	if def.Transport == "http" {
-		lines = append(lines, fmt.Sprintf("url = %q", def.URL))
-		if len(def.Headers) > 0 {
-			lines = append(lines, "")
-			lines = append(lines, fmt.Sprintf("[mcp_servers.%s.http_headers]", def.Name))
-			for key, value := range def.Headers {
-				lines = append(lines, fmt.Sprintf("%s = %q", key, value))
+func (d *tomlDocument) arrayTables(path []string) (string, error) {
+	arrays := d.arrayTablePaths(path)
+	if len(arrays) == 0 {
+		return "", nil
+	}
+	ranges, err := d.ownedRanges(path)
+	if err != nil {
+		return "", err
+	}
+	var blocks []string
+	for _, r := range ranges {
+		if !d.isTableRange(r) {
+			continue
+		}
+		header := d.stmts[r[0]]
+		for i := r[0]; i < r[1]; i++ {
+			if d.stmts[i].kind == tomlTable || d.stmts[i].kind == tomlArrayTable {
+				header = d.stmts[i]
+				break
 			}
 		}
-		return lines
+		if insideTomlArray(header.path, arrays) {
+			text := d.src[d.stmts[r[0]].start:d.stmts[r[1]-1].end]
+			blocks = append(blocks, strings.TrimRight(text, "\r\n")+"\n")
+		}
+	}
+	return strings.Join(blocks, "\n"), nil
+}
+
+func (d *tomlDocument) rawValues(path []string) ([]tomlStatement, error) {
+	var values []tomlStatement
+	arrays := d.arrayTablePaths(path)
+	for _, stmt := range d.stmts {
+		if stmt.kind != tomlKeyValue || !hasTomlPrefix(stmt.path, path) || insideTomlArray(stmt.path, arrays) {
+			continue
+		}
+		if len(stmt.path) > len(path) {
+			values = append(values, stmt)
+			continue
+		}
+		if _, ok := stmt.value.(map[string]any); !ok {
+			continue
+		}
+		fields, err := inlineTableFields(stmt.raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", formatTomlPath(path...), err)
+		}
+		for _, field := range fields {
+			field.path = append(append([]string{}, path...), field.key...)
+			values = append(values, field)
+		}
 	}
+	return values, nil
+}
 
-	lines = append(lines, fmt.Sprintf("command = %q", def.Command))
-	if len(def.Args) > 0 {
-		lines = append(lines, fmt.Sprintf("args = [%s]", joinTomlArray(def.Args)))
+func inlineTableFields(raw string) ([]tomlStatement, error) {
+	p := &tomlParser{src: raw, line: 1}
+	if p.peek() != '{' {
+		return nil, p.errorf("expected inline table")
+	}
+	p.pos++
+	var fields []tomlStatement
+	for {
+		p.skipWhitespaceAndComments()
+		if p.peek() == '}' {
+			return fields, nil
+		}
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peek() != '=' {
+			return nil, p.errorf("expected = in inline table")
+		}
+		p.pos++
+		p.skipSpaces()
+		valueStart := p.pos
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, tomlStatement{kind: tomlKeyValue, key: key, value: value, raw: raw[valueStart:p.pos]})
+		p.skipWhitespaceAndComments()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
 	}
-	if len(def.Env) > 0 {
-		lines = append(lines, "")
-		lines = append(lines, fmt.Sprintf("[mcp_servers.%s.env]", def.Name))
-		for key, value := range def.Env {
-			lines = append(lines, fmt.Sprintf("%s = %q", key, value))
+}
+
+func lookupTomlPath(value any, path []string) bool {
+	for _, part := range path {
+		table, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = table[part]; !ok {
+			return false
 		}
 	}
-	return lines
+	return true
+}
+
+func startsWithTomlBlankLine(src string) bool {
+	line, _, found := strings.Cut(src, "\n")
+	return found && strings.TrimSpace(line) == ""
+}
+
+func endsWithTomlBlankLine(src string) bool {
+	return src == "" || strings.HasSuffix(strings.TrimRight(src, " \t\r"), "\n\n")
+}
+
+func formatTomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for i := 0; i < len(key); i++ {
+		if !isTomlBareKeyChar(key[i]) {
+			return formatTomlString(key)
+		}
+	}
+	return key
+}
+
+func formatTomlPath(path ...string) string {
+	parts := make([]string, 0, len(path))
+	for _, part := range path {
+		parts = append(parts, formatTomlKey(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+func formatTomlString(value string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&out, `\u%04X`, r)
+				continue
+			}
+			out.WriteRune(r)
+		}
+	}
+	out.WriteByte('"')
+	return out.String()
 }
 
-func joinTomlArray(values []string) string {
-	escaped := make([]string, 0, len(values))
+func formatTomlStringArray(values []string) string {
+	items := make([]string, 0, len(values))
 	for _, value := range values {
-		escaped = append(escaped, fmt.Sprintf("%q", value))
+		items = append(items, formatTomlString(value))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func formatTomlNumber(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatTomlStringTable(header string, values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := []string{"", "[" + header + "]"}
+	for _, key := range keys {
+		lines = append(lines, formatTomlKey(key)+" = "+formatTomlString(values[key]))
+	}
+	return lines
+}
+
+func tomlNumber(value any) float64 {
+	switch typed := value.(type) {
+	case int64:
+		return float64(typed)
+	case float64:
+		return typed
+	default:
+		return 0
 	}
-	return strings.Join(escaped, ", ")
 }
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func codexConfig(t *testing.T, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	path := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCodexInstallPreservesDocument(t *testing.T) {
+	def := Definition{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"-y", "docs"}}
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "empty file",
+			in:   "",
+			want: "[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\n",
+		},
+		{
+			name: "appends after other settings",
+			in:   "model = \"o3\" # default model\n",
+			want: "model = \"o3\" # default model\n\n[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\n",
+		},
+		{
+			name: "keeps comments above the replaced table",
+			in: "model = \"o3\"\n\n# docs server\n# keep me\n[mcp_servers.docs]\ncommand = \"old\"\n\n" +
+				"[mcp_servers.docs.env]\nA = \"1\"\n\n# other\n[mcp_servers.other]\ncommand = \"x\"\n",
+			want: "model = \"o3\"\n\n# docs server\n# keep me\n[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\n\n" +
+				"# other\n[mcp_servers.other]\ncommand = \"x\"\n",
+		},
+		{
+			name: "keeps unmanaged keys",
+			in:   "[mcp_servers.docs]\ncommand = \"old\"\ntools = { search = true }\nstartup_timeout_sec = 20\n",
+			want: "[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\ntools = { search = true }\nstartup_timeout_sec = 20\n",
+		},
+		{
+			name: "keeps unmanaged fields of an inline table",
+			in:   "[mcp_servers]\ndocs = { command = \"old\", cwd = \"/srv\", env = { A = \"1\" } }\n",
+			want: "[mcp_servers]\n\n[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\ncwd = \"/srv\"\n",
+		},
+		{
+			name: "keeps array-of-tables verbatim and rewrites env tables",
+			in: "[mcp_servers.docs]\ncommand = \"old\"\nenabled = false\n\n[mcp_servers.docs.env]\nA = \"1\"\n\n# first tool\n[[mcp_servers.docs.tools]]\nname = \"t1\"\n\n" +
+				"[mcp_servers.docs.tools.config]\nlevel = 2\n\n[[mcp_servers.docs.tools]]\nname = \"t2\"\n\n[mcp_servers.other]\ncommand = \"x\"\n",
+			want: "[mcp_servers.docs]\ncommand = \"npx\"\nargs = [\"-y\", \"docs\"]\nenabled = false\n\n# first tool\n[[mcp_servers.docs.tools]]\nname = \"t1\"\n\n" +
+				"[mcp_servers.docs.tools.config]\nlevel = 2\n\n[[mcp_servers.docs.tools]]\nname = \"t2\"\n\n[mcp_servers.other]\ncommand = \"x\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := codexConfig(t, tt.in)
+			if _, err := (codexAdapter{}).Install(def, installer.ScopeUser, "", true); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("config:\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodexUninstallRemovesTableAndComment(t *testing.T) {
+	path := codexConfig(t, "model = \"o3\"\n\n# docs server\n[mcp_servers.docs]\ncommand = \"npx\"\n\n[mcp_servers.docs.env]\nA = \"1\"\n\n# other\n[mcp_servers.other]\ncommand = \"x\"\n")
+	if _, err := (codexAdapter{}).Uninstall("docs", installer.ScopeUser, "", false); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "model = \"o3\"\n\n# other\n[mcp_servers.other]\ncommand = \"x\"\n"
+	if string(data) != want {
+		t.Fatalf("config:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestCodexRoundTrip(t *testing.T) {
+	tests := []Definition{
+		{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"-y", "docs"}, Env: map[string]string{"MODE": "fast", "TOKEN": SecretReference("TOKEN")}},
+		{Name: "remote", Transport: "http", URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer " + SecretReference("TOKEN"), "X-Api-Key": SecretReference("API_KEY"), "X-Team": "core"}},
+		{Name: "quoted.name", Transport: "stdio", Command: "C:\\Tools\\server.exe", ToolTimeoutSec: 90, Disabled: true},
+	}
+	for _, def := range tests {
+		t.Run(def.Name, func(t *testing.T) {
+			codexConfig(t, "# settings\nmodel = \"o3\"\n")
+			if _, err := (codexAdapter{}).Install(def, installer.ScopeUser, "", false); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			got, ok, err := (codexAdapter{}).Read(def.Name, installer.ScopeUser, "")
+			if err != nil || !ok {
+				t.Fatalf("Read: ok=%v err=%v", ok, err)
+			}
+			want, err := normalizeDefinition(def, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("read back %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCodexArrayOfTablesRoundTrip(t *testing.T) {
+	path := codexConfig(t, "[mcp_servers.docs]\ncommand = \"old\"\n\n[mcp_servers.docs.env]\nA = \"1\"\n\n[[mcp_servers.docs.tools]]\nname = \"t1\"\n\n"+
+		"[mcp_servers.docs.tools.config]\nlevel = 2\n\n[[mcp_servers.docs.tools]]\nname = \"t2\"\n")
+	def := Definition{Name: "docs", Transport: "stdio", Command: "npx", Env: map[string]string{"B": "2", "TOKEN": SecretReference("TOKEN")}}
+	for i := 0; i < 2; i++ {
+		if _, err := (codexAdapter{}).Install(def, installer.ScopeUser, "", true); err != nil {
+			t.Fatalf("Install #%d: %v", i+1, err)
+		}
+	}
+	doc, err := loadTomlDocument(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	server := doc.tables([]string{codexSection})["docs"]
+	want := []any{
+		map[string]any{"name": "t1", "config": map[string]any{"level": int64(2)}},
+		map[string]any{"name": "t2"},
+	}
+	if !reflect.DeepEqual(server["tools"], want) {
+		t.Fatalf("tools = %#v, want %#v", server["tools"], want)
+	}
+	if !reflect.DeepEqual(server["env"], map[string]any{"B": "2"}) {
+		t.Fatalf("env = %#v", server["env"])
+	}
+	got, ok, err := (codexAdapter{}).Read("docs", installer.ScopeUser, "")
+	if err != nil || !ok {
+		t.Fatalf("Read: ok=%v err=%v", ok, err)
+	}
+	if want, _ := normalizeDefinition(def, ""); !reflect.DeepEqual(got, want) {
+		t.Fatalf("read back %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTomlDuplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		valid bool
+	}{
+		{"distinct keys", "[a]\nx = 1\ny = 2\n", true},
+		{"array elements", "[[a.t]]\nname = 1\n[a.t.c]\nv = 1\n[[a.t]]\nname = 2\n[a.t.c]\nv = 2\n", true},
+		{"duplicate key", "[a]\nx = 1\nx = 2\n", false},
+		{"duplicate dotted key", "[a]\nt.name = \"t1\"\nt.name = \"t2\"\n", false},
+		{"duplicate table", "[a]\nx = 1\n\n[a]\ny = 2\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTomlDocument(tt.src)
+			if tt.valid && err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("parse accepted a duplicate definition")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"path/filepath"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func newWindsurfAdapter() ClientAdapter {
+	return jsonAdapter{
+		client: installer.ToolWindsurf,
+		userPath: func(home string) string {
+			return filepath.Join(home, ".codeium", "windsurf", "mcp_config.json")
+		},
+		section:    "mcpServers",
+		toServer:   toWindsurfServer,
+		fromServer: fromWindsurfServer,
+	}
+}
+
+func toWindsurfServer(def Definition) map[string]any {
+	if def.Transport == "http" {
+		server := map[string]any{
+			"serverUrl": def.URL,
+		}
+		if len(def.Headers) > 0 {
+			server["headers"] = def.Headers
+		}
+		return server
+	}
+	return toCursorServer(def)
+}
+
+func fromWindsurfServer(name string, server map[string]any) (Definition, error) {
+	def, err := fromClaudeServer(name, server)
+	if err != nil {
+		return Definition{}, err
+	}
+	if def.URL == "" {
+		def.URL = stringValue(server["serverUrl"])
+	}
+	return def, nil
+}
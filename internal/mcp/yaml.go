@@ -0,0 +1,619 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-skill-manager/internal/safefile"
+)
+
+type yamlDocument struct {
+	lines []string
+}
+
+type yamlField struct {
+	key   string
+	value any
+}
+
+type yamlRawField struct {
+	key      string
+	comments []string
+	lines    []string
+}
+
+type yamlEntry struct {
+	name  string
+	start int
+	end   int
+}
+
+func loadYAMLDocument(path string) (*yamlDocument, error) {
+	data, err := safefile.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &yamlDocument{}, nil
+		}
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return &yamlDocument{}, nil
+	}
+	return &yamlDocument{lines: strings.Split(text, "\n")}, nil
+}
+
+func (d *yamlDocument) save(path string) error {
+	data := strings.Join(d.lines, "\n")
+	if len(data) > 0 {
+		data += "\n"
+	}
+	return safefile.WriteFile(path, []byte(data), 0o644)
+}
+
+func (d *yamlDocument) section(key string) (start, end int, found bool) {
+	start = -1
+	for i, line := range d.lines {
+		if isYAMLBlank(line) || yamlIndent(line) != 0 {
+			continue
+		}
+		name, _, ok := splitYAMLKey(line)
+		if ok && name == key {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return 0, 0, false
+	}
+	end = len(d.lines)
+	for i := start + 1; i < len(d.lines); i++ {
+		if !isYAMLBlank(d.lines[i]) && yamlIndent(d.lines[i]) == 0 {
+			end = i
+			break
+		}
+	}
+	for end > start+1 && isYAMLBlank(d.lines[end-1]) {
+		end--
+	}
+	return start, end, true
+}
+
+func (d *yamlDocument) entries(key string) []yamlEntry {
+	start, end, ok := d.section(key)
+	if !ok {
+		return nil
+	}
+	childIndent := d.childIndent(start, end)
+	var entries []yamlEntry
+	for i := start + 1; i < end; i++ {
+		line := d.lines[i]
+		if isYAMLBlank(line) || yamlIndent(line) != childIndent {
+			continue
+		}
+		name, _, ok := splitYAMLKey(line)
+		if !ok {
+			continue
+		}
+		if len(entries) > 0 {
+			entries[len(entries)-1].end = trimYAMLTrailing(d.lines, entries[len(entries)-1].start, i)
+		}
+		entries = append(entries, yamlEntry{name: name, start: i, end: end})
+	}
+	return entries
+}
+
+func (d *yamlDocument) findEntry(key, name string) (yamlEntry, bool) {
+	for _, entry := range d.entries(key) {
+		if entry.name == name {
+			return entry, true
+		}
+	}
+	return yamlEntry{}, false
+}
+
+func (d *yamlDocument) entryValue(entry yamlEntry) map[string]any {
+	value, _ := parseYAMLNode(d.lines[entry.start+1 : entry.end]).(map[string]any)
+	if value == nil {
+		value = map[string]any{}
+	}
+	return value
+}
+
+func (d *yamlDocument) setEntry(key, name string, fields []yamlField, owned map[string]bool) error {
+	if entry, ok := d.findEntry(key, name); ok {
+		lines, err := d.mergeEntry(key, entry, fields, owned)
+		if err != nil {
+			return err
+		}
+		d.splice(entry.start, entry.end, lines)
+		return nil
+	}
+	start, end, ok := d.section(key)
+	if !ok {
+		if len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) != "" {
+			d.lines = append(d.lines, "")
+		}
+		d.lines = append(d.lines, key+":")
+		d.lines = append(d.lines, renderYAMLEntry(name, fields, 2)...)
+		return nil
+	}
+	switch _, rest, _ := splitYAMLKey(d.lines[start]); rest {
+	case "":
+	case "{}":
+		d.lines[start] = key + ":"
+	default:
+		return fmt.Errorf("%s is a flow mapping; cannot edit in place", key)
+	}
+	indent := d.childIndent(start, end)
+	d.splice(end, end, renderYAMLEntry(name, fields, indent))
+	return nil
+}
+
+func (d *yamlDocument) mergeEntry(key string, entry yamlEntry, fields []yamlField, owned map[string]bool) ([]string, error) {
+	indent := yamlIndent(d.lines[entry.start])
+	switch _, rest, _ := splitYAMLKey(d.lines[entry.start]); rest {
+	case "":
+	case "{}":
+		return renderYAMLEntry(entry.name, fields, indent), nil
+	default:
+		return nil, fmt.Errorf("%s.%s is a flow mapping; cannot edit in place", key, entry.name)
+	}
+	inner, existing := d.entryFields(entry)
+	if inner == 0 {
+		return renderYAMLEntry(entry.name, fields, indent), nil
+	}
+	pad := strings.Repeat(" ", inner)
+	byKey := map[string]yamlField{}
+	for _, field := range fields {
+		byKey[field.key] = field
+	}
+	present := map[string]bool{}
+	lines := []string{d.lines[entry.start]}
+	for _, raw := range existing {
+		present[raw.key] = true
+		field, ok := byKey[raw.key]
+		switch {
+		case raw.key == "" || !owned[raw.key]:
+			lines = append(lines, raw.comments...)
+			lines = append(lines, raw.lines...)
+		case ok:
+			lines = append(lines, raw.comments...)
+			lines = append(lines, renderYAMLField(field, pad)...)
+		}
+	}
+	for _, field := range fields {
+		if !present[field.key] {
+			lines = append(lines, renderYAMLField(field, pad)...)
+		}
+	}
+	return lines, nil
+}
+
+func (d *yamlDocument) entryFields(entry yamlEntry) (int, []yamlRawField) {
+	inner := 0
+	var fields []yamlRawField
+	var pending []string
+	for i := entry.start + 1; i < entry.end; i++ {
+		line := d.lines[i]
+		if isYAMLBlank(line) {
+			pending = append(pending, line)
+			continue
+		}
+		if inner == 0 {
+			inner = yamlIndent(line)
+		}
+		if yamlIndent(line) == inner {
+			if key, _, ok := splitYAMLKey(line); ok {
+				fields = append(fields, yamlRawField{key: key, comments: pending, lines: []string{line}})
+				pending = nil
+				continue
+			}
+		}
+		if len(fields) == 0 {
+			fields = append(fields, yamlRawField{})
+		}
+		last := &fields[len(fields)-1]
+		last.lines = append(append(last.lines, pending...), line)
+		pending = nil
+	}
+	if len(pending) > 0 {
+		fields = append(fields, yamlRawField{lines: pending})
+	}
+	return inner, fields
+}
+
+func (d *yamlDocument) removeEntry(key, name string) bool {
+	entry, ok := d.findEntry(key, name)
+	if !ok {
+		return false
+	}
+	start := entry.start
+	indent := yamlIndent(d.lines[entry.start])
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(d.lines[start-1]), "#") && yamlIndent(d.lines[start-1]) >= indent {
+		start--
+	}
+	d.splice(start, entry.end, nil)
+	if start > 0 && start < len(d.lines) && isYAMLBlank(d.lines[start-1]) && isYAMLBlank(d.lines[start]) {
+		d.splice(start, start+1, nil)
+	}
+	if len(d.entries(key)) == 0 {
+		if start, _, ok := d.section(key); ok {
+			d.lines[start] = key + ": {}"
+		}
+	}
+	return true
+}
+
+func (d *yamlDocument) childIndent(start, end int) int {
+	for i := start + 1; i < end; i++ {
+		if !isYAMLBlank(d.lines[i]) {
+			if indent := yamlIndent(d.lines[i]); indent > 0 {
+				return indent
+			}
+		}
+	}
+	return 2
+}
+
+func (d *yamlDocument) splice(start, end int, lines []string) {
+	updated := make([]string, 0, len(d.lines)-(end-start)+len(lines))
+	updated = append(updated, d.lines[:start]...)
+	updated = append(updated, lines...)
+	updated = append(updated, d.lines[end:]...)
+	d.lines = updated
+}
+
+func trimYAMLTrailing(lines []string, start, end int) int {
+	for end > start+1 && isYAMLBlank(lines[end-1]) {
+		end--
+	}
+	return end
+}
+
+func renderYAMLEntry(name string, fields []yamlField, indent int) []string {
+	pad := strings.Repeat(" ", indent)
+	lines := []string{pad + yamlScalar(name) + ":"}
+	for _, field := range fields {
+		lines = append(lines, renderYAMLField(field, pad+"  ")...)
+	}
+	return lines
+}
+
+func renderYAMLField(field yamlField, inner string) []string {
+	prefix := inner + yamlScalar(field.key) + ":"
+	switch value := field.value.(type) {
+	case []string:
+		if len(value) == 0 {
+			return []string{prefix + " []"}
+		}
+		lines := []string{prefix}
+		for _, item := range value {
+			lines = append(lines, inner+"- "+yamlScalar(item))
+		}
+		return lines
+	case map[string]string:
+		if len(value) == 0 {
+			return []string{prefix + " {}"}
+		}
+		lines := []string{prefix}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lines = append(lines, inner+"  "+yamlScalar(key)+": "+yamlScalar(value[key]))
+		}
+		return lines
+	case bool:
+		return []string{prefix + " " + strconv.FormatBool(value)}
+	case int:
+		return []string{prefix + " " + strconv.Itoa(value)}
+	case string:
+		return []string{prefix + " " + yamlScalar(value)}
+	}
+	return []string{prefix + " null"}
+}
+
+func yamlScalar(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@` \t") ||
+		strings.HasSuffix(value, " ") ||
+		strings.Contains(value, ": ") ||
+		strings.Contains(value, " #") ||
+		strings.ContainsAny(value, "\n\t\\") {
+		return strconv.Quote(value)
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return strconv.Quote(value)
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func isYAMLBlank(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+func yamlIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func splitYAMLKey(line string) (string, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+		return "", "", false
+	}
+	var key, rest string
+	switch trimmed[0] {
+	case '"', '\'':
+		value, remaining, ok := parseYAMLQuoted(trimmed)
+		if !ok {
+			return "", "", false
+		}
+		remaining = strings.TrimLeft(remaining, " ")
+		if !strings.HasPrefix(remaining, ":") {
+			return "", "", false
+		}
+		key, rest = value, remaining[1:]
+	default:
+		idx := -1
+		for i := 0; i < len(trimmed); i++ {
+			if trimmed[i] == ':' && (i+1 == len(trimmed) || trimmed[i+1] == ' ' || trimmed[i+1] == '\t') {
+				idx = i
+				break
+			}
+		}
+		if idx <= 0 {
+			return "", "", false
+		}
+		key, rest = strings.TrimSpace(trimmed[:idx]), trimmed[idx+1:]
+	}
+	return key, stripYAMLComment(rest), true
+}
+
+func stripYAMLComment(value string) string {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+func parseYAMLNode(lines []string) any {
+	for _, line := range lines {
+		if isYAMLBlank(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return parseYAMLSequence(lines)
+		}
+		return parseYAMLMapping(lines)
+	}
+	return nil
+}
+
+func parseYAMLMapping(lines []string) map[string]any {
+	result := map[string]any{}
+	indent := -1
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isYAMLBlank(line) {
+			continue
+		}
+		current := yamlIndent(line)
+		if indent == -1 {
+			indent = current
+		}
+		if current != indent {
+			continue
+		}
+		key, rest, ok := splitYAMLKey(line)
+		if !ok {
+			continue
+		}
+		if rest != "" {
+			result[key] = parseYAMLValue(rest)
+			continue
+		}
+		j := i + 1
+		for ; j < len(lines); j++ {
+			if isYAMLBlank(lines[j]) {
+				continue
+			}
+			trimmed := strings.TrimSpace(lines[j])
+			child := yamlIndent(lines[j])
+			if child > indent || (child == indent && (strings.HasPrefix(trimmed, "- ") || trimmed == "-")) {
+				continue
+			}
+			break
+		}
+		result[key] = parseYAMLNode(lines[i+1 : j])
+		i = j - 1
+	}
+	return result
+}
+
+func parseYAMLSequence(lines []string) []any {
+	var items []any
+	indent := -1
+	for _, line := range lines {
+		if isYAMLBlank(line) {
+			continue
+		}
+		if indent == -1 {
+			indent = yamlIndent(line)
+		}
+		trimmed := strings.TrimSpace(line)
+		if yamlIndent(line) != indent || !(strings.HasPrefix(trimmed, "- ") || trimmed == "-") {
+			continue
+		}
+		items = append(items, parseYAMLValue(stripYAMLComment(strings.TrimPrefix(trimmed, "-"))))
+	}
+	return items
+}
+
+func parseYAMLValue(value string) any {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		parsed, _ := parseYAMLFlow(value)
+		return parsed
+	}
+	return parseYAMLScalar(value)
+}
+
+func parseYAMLScalar(value string) any {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if value[0] == '"' || value[0] == '\'' {
+		text, _, ok := parseYAMLQuoted(value)
+		if ok {
+			return text
+		}
+		return value
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null", "~":
+		return nil
+	}
+	return value
+}
+
+func parseYAMLQuoted(value string) (string, string, bool) {
+	if value == "" {
+		return "", "", false
+	}
+	if value[0] == '"' {
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", "", false
+		}
+		text, err := strconv.Unquote(prefix)
+		if err != nil {
+			return "", "", false
+		}
+		return text, value[len(prefix):], true
+	}
+	if value[0] != '\'' {
+		return "", "", false
+	}
+	var text strings.Builder
+	for i := 1; i < len(value); i++ {
+		if value[i] != '\'' {
+			text.WriteByte(value[i])
+			continue
+		}
+		if i+1 < len(value) && value[i+1] == '\'' {
+			text.WriteByte('\'')
+			i++
+			continue
+		}
+		return text.String(), value[i+1:], true
+	}
+	return "", "", false
+}
+
+func parseYAMLFlow(value string) (any, string) {
+	value = strings.TrimLeft(value, " \t")
+	if value == "" {
+		return nil, ""
+	}
+	switch value[0] {
+	case '[':
+		var items []any
+		value = value[1:]
+		for {
+			value = strings.TrimLeft(value, " \t,")
+			if value == "" {
+				return items, ""
+			}
+			if value[0] == ']' {
+				return items, value[1:]
+			}
+			var item any
+			item, value = parseYAMLFlow(value)
+			items = append(items, item)
+		}
+	case '{':
+		items := map[string]any{}
+		value = value[1:]
+		for {
+			value = strings.TrimLeft(value, " \t,")
+			if value == "" {
+				return items, ""
+			}
+			if value[0] == '}' {
+				return items, value[1:]
+			}
+			var key any
+			key, value = parseYAMLFlowScalar(value, ":,}")
+			value = strings.TrimLeft(value, " \t")
+			if !strings.HasPrefix(value, ":") {
+				items[yamlString(key)] = nil
+				continue
+			}
+			var item any
+			item, value = parseYAMLFlow(value[1:])
+			items[yamlString(key)] = item
+		}
+	default:
+		return parseYAMLFlowScalar(value, ",]}")
+	}
+}
+
+func parseYAMLFlowScalar(value, stops string) (any, string) {
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		text, rest, ok := parseYAMLQuoted(value)
+		if ok {
+			return text, rest
+		}
+	}
+	end := strings.IndexAny(value, stops)
+	if end == -1 {
+		end = len(value)
+	}
+	return parseYAMLScalar(value[:end]), value[end:]
+}
+
+func yamlString(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		return ""
+	}
+}
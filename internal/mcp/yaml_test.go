@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+func gooseConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+	path := gooseConfigPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readConfig(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+const gooseDocsEntry = `  docs:
+    enabled: true
+    name: docs
+    type: stdio
+    cmd: npx
+    args:
+    - "-y"
+    - docs
+    envs:
+      MODE: fast
+    env_keys:
+    - TOKEN
+    description: ""
+    timeout: 300
+`
+
+func TestGooseInstallPreservesDocument(t *testing.T) {
+	def := Definition{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"-y", "docs"}, Env: map[string]string{"MODE": "fast", "TOKEN": SecretReference("TOKEN")}}
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "empty file",
+			in:   "",
+			want: "extensions:\n" + gooseDocsEntry,
+		},
+		{
+			name: "appends a section after other settings",
+			in:   "GOOSE_MODEL: gpt # default model\n",
+			want: "GOOSE_MODEL: gpt # default model\n\nextensions:\n" + gooseDocsEntry,
+		},
+		{
+			name: "fills an empty inline section",
+			in:   "extensions: {}\nGOOSE_MODEL: gpt\n",
+			want: "extensions:\n" + gooseDocsEntry + "GOOSE_MODEL: gpt\n",
+		},
+		{
+			name: "replaces an entry in place and keeps its neighbours",
+			in: "GOOSE_PROVIDER: openai\nextensions:\n  developer:\n    enabled: true\n    type: builtin\n\n  # docs server\n  docs:\n    enabled: true\n    name: docs\n    type: stdio\n    cmd: old\n    args: []\n    envs: {}\n    env_keys: []\n    description: \"\"\n    timeout: 300\n\n" +
+				"  other:\n    cmd: x\n    type: stdio\nGOOSE_MODEL: gpt\n",
+			want: "GOOSE_PROVIDER: openai\nextensions:\n  developer:\n    enabled: true\n    type: builtin\n\n  # docs server\n" + gooseDocsEntry + "\n" +
+				"  other:\n    cmd: x\n    type: stdio\nGOOSE_MODEL: gpt\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := gooseConfig(t, tt.in)
+			if _, err := (gooseAdapter{}).Install(def, installer.ScopeUser, "", true); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			if got := readConfig(t, path); got != tt.want {
+				t.Fatalf("config:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGooseInstallKeepsUserFields(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Definition
+		in   string
+		want string
+	}{
+		{
+			name: "keeps enabled, timeout, description and unknown keys",
+			def:  Definition{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"docs"}},
+			in: "extensions:\n  docs:\n    enabled: false\n    name: Docs\n    type: stdio\n    cmd: old\n    args:\n    - old\n    # tuned by hand\n    timeout: 60\n" +
+				"    description: project docs\n    bundled: true\n    available_tools:\n    - search\n",
+			want: "extensions:\n  docs:\n    enabled: false\n    name: Docs\n    type: stdio\n    cmd: npx\n    args:\n    - docs\n    # tuned by hand\n    timeout: 60\n" +
+				"    description: project docs\n    bundled: true\n    available_tools:\n    - search\n    envs: {}\n    env_keys: []\n",
+		},
+		{
+			name: "drops stdio fields when switching to http",
+			def:  Definition{Name: "docs", Transport: "http", URL: "https://example.com/mcp"},
+			in:   "extensions:\n    docs:\n        enabled: false\n        type: stdio\n        cmd: npx\n        args: []\n        timeout: 60\n",
+			want: "extensions:\n    docs:\n        enabled: false\n        type: streamable_http\n        timeout: 60\n        name: docs\n        uri: https://example.com/mcp\n" +
+				"        headers: {}\n        envs: {}\n        env_keys: []\n        description: \"\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := gooseConfig(t, tt.in)
+			if _, err := (gooseAdapter{}).Install(tt.def, installer.ScopeUser, "", true); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			if got := readConfig(t, path); got != tt.want {
+				t.Fatalf("config:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGooseUninstall(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "removes the entry and its comment",
+			in:   "extensions:\n  developer:\n    type: builtin\n\n  # docs server\n  docs:\n    cmd: npx\n\n  other:\n    cmd: x\nGOOSE_MODEL: gpt\n",
+			want: "extensions:\n  developer:\n    type: builtin\n\n  other:\n    cmd: x\nGOOSE_MODEL: gpt\n",
+		},
+		{
+			name: "leaves an empty section",
+			in:   "GOOSE_MODEL: gpt\n\nextensions:\n  docs:\n    cmd: npx\n",
+			want: "GOOSE_MODEL: gpt\n\nextensions: {}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := gooseConfig(t, tt.in)
+			if _, err := (gooseAdapter{}).Uninstall("docs", installer.ScopeUser, "", false); err != nil {
+				t.Fatalf("Uninstall: %v", err)
+			}
+			if got := readConfig(t, path); got != tt.want {
+				t.Fatalf("config:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGooseBuiltinExtensionsAreUnmanaged(t *testing.T) {
+	in := "extensions:\n  developer:\n    enabled: true\n    name: developer\n    type: builtin\n  todo:\n    type: platform\n  docs:\n    cmd: npx\n    type: stdio\n"
+	path := gooseConfig(t, in)
+	adapter := gooseAdapter{}
+
+	entries, _, err := adapter.List(installer.ScopeUser, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if want := []Entry{{Name: "docs", Transport: "stdio"}}; !reflect.DeepEqual(entries, want) {
+		t.Fatalf("List = %+v, want %+v", entries, want)
+	}
+	if _, ok, err := adapter.Read("developer", installer.ScopeUser, ""); ok || err != nil {
+		t.Fatalf("Read(developer) = ok %v, err %v; want not found", ok, err)
+	}
+	if _, err := adapter.Uninstall("developer", installer.ScopeUser, "", true); err == nil || !strings.Contains(err.Error(), "builtin") {
+		t.Fatalf("Uninstall(developer) err = %v, want a builtin error", err)
+	}
+	if _, err := adapter.Install(Definition{Name: "developer", Transport: "http", URL: "https://example.com/mcp"}, installer.ScopeUser, "", true); err == nil {
+		t.Fatal("Install over a builtin extension succeeded")
+	}
+	if got := readConfig(t, path); got != in {
+		t.Fatalf("config changed:\n%s", got)
+	}
+}
+
+func TestGooseInstallRejectsFlowMapping(t *testing.T) {
+	in := "extensions: {developer: {type: builtin, enabled: true}}\nGOOSE_MODEL: gpt\n"
+	path := gooseConfig(t, in)
+	def := Definition{Name: "docs", Transport: "stdio", Command: "npx"}
+	if _, err := (gooseAdapter{}).Install(def, installer.ScopeUser, "", true); err == nil || !strings.Contains(err.Error(), "flow mapping") {
+		t.Fatalf("Install err = %v, want a flow mapping error", err)
+	}
+	if got := readConfig(t, path); got != in {
+		t.Fatalf("config changed:\n%s", got)
+	}
+}
+
+func TestGooseRoundTrip(t *testing.T) {
+	tests := []Definition{
+		{Name: "docs", Transport: "stdio", Command: "npx", Args: []string{"-y", "docs: latest", "#tag"}, Env: map[string]string{"MODE": "yes", "TOKEN": SecretReference("TOKEN")}},
+		{Name: "remote", Transport: "http", URL: "https://example.com/mcp", Headers: map[string]string{"X-Team": "core", "X-Port": "8080"}},
+		{Name: "windows", Transport: "stdio", Command: "C:\\Tools\\server.exe", Args: []string{""}},
+	}
+	for _, def := range tests {
+		t.Run(def.Name, func(t *testing.T) {
+			gooseConfig(t, "# goose settings\nGOOSE_MODEL: gpt\n")
+			if _, err := (gooseAdapter{}).Install(def, installer.ScopeUser, "", false); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			got, ok, err := (gooseAdapter{}).Read(def.Name, installer.ScopeUser, "")
+			if err != nil || !ok {
+				t.Fatalf("Read: ok=%v err=%v", ok, err)
+			}
+			want, err := normalizeDefinition(def, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("read back %+v, want %+v", got, want)
+			}
+		})
+	}
+}
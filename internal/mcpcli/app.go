@@ -39,6 +39,26 @@ func (a *App) Run(args []string) int {
 		return a.runUninstall(args[1:])
 	case "clean":
 		return a.runClean(args[1:])
+	case "sync":
+		return a.runSync(args[1:])
+	case "backups":
+		return a.runBackups(args[1:])
+	case "restore":
+		return a.runRestore(args[1:])
+	case "doctor":
+		return a.runDoctor(args[1:])
+	case "test":
+		return a.runTest(args[1:])
+	case "tools":
+		return a.runTools(args[1:])
+	case "call":
+		return a.runCall(args[1:])
+	case "secrets":
+		return a.runSecrets(args[1:])
+	case "config":
+		return a.runConfig(args[1:])
+	case "registry":
+		return a.runRegistry(args[1:])
 	default:
 		fmt.Fprintf(a.errOut, "unknown command: %s\n", args[0])
 		a.printHelp()
@@ -56,6 +76,16 @@ Commands:
   update|upgrade        Update installed MCP servers from registry
   uninstall|remove|rm  Remove installed MCP servers
   clean                Clear local store (~/.mcp-skill/skill and ~/.mcp-skill/mcp)
+  sync                 Reconcile client configs with the project manifest (.mcp-skill.json)
+  backups [list]       List config snapshots taken before each change
+  restore <id>         Restore a client config from a backup
+  doctor               Check client configs, servers, cache records and the registry index
+  test <name>          Start/connect to a server and run the MCP handshake and list calls
+  tools <name> [tool]  List a server's tools with descriptions and input schemas
+  call <name> <tool>   Invoke a server tool with --arg key=value
+  secrets [action]     Store secret inputs (API keys, tokens) outside client configs
+  config <action>      Get, set or unset the saved registry inputs of a server
+  registry [action]    List, add or remove registries (merged by priority)
 
 Use "%s <command> -h" for command help.
 `, a.binaryName, a.binaryName)
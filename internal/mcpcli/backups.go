@@ -0,0 +1,87 @@
+package mcpcli
+
+import (
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+)
+
+func (a *App) runBackups(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		a.printBackupsHelp()
+		return 0
+	}
+	if len(args) > 0 && args[0] == "list" {
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("backups", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	clientFlag := fs.String("client", "", "comma-separated clients to show")
+	clientShort := fs.String("c", "", "alias for --client")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printBackupsHelp()
+		return 0
+	}
+	if len(positionals) > 0 {
+		fmt.Fprintf(a.errOut, "unknown backups command: %s\n", positionals[0])
+		return 2
+	}
+
+	var clients []installer.Tool
+	clientValue := *clientFlag
+	if *clientShort != "" {
+		clientValue = *clientShort
+	}
+	if clientValue != "" {
+		parsed, err := installer.ParseTools(clientValue)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
+			return 2
+		}
+		clients = parsed
+	}
+
+	backups, err := mcp.ListBackups(clients)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "backups failed: %v\n", err)
+		return 1
+	}
+	if len(backups) == 0 {
+		fmt.Fprintln(a.out, "no backups found")
+		return 0
+	}
+
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tCREATED\tOPERATION\tPATH")
+	for _, backup := range backups {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", backup.ID, backup.CreatedAt.Local().Format(time.DateTime), backup.Operation, backup.Path)
+	}
+	writer.Flush()
+	return 0
+}
+
+func (a *App) printBackupsHelp() {
+	fmt.Fprintf(a.out, `Usage: %s backups [list] [--client|-c <list>]
+
+What it does:
+  - Lists snapshots taken before each change to a client config
+  - Backups live in ~/.mcp-skill/backups/<client>/<timestamp> (last 20 per client are kept)
+  - Restore one with "%s restore <id>"
+
+Examples:
+  %s backups
+  %s backups list -c claude
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
@@ -0,0 +1,278 @@
+package mcpcli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/mcpclient"
+)
+
+type argList []string
+
+func (l *argList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *argList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type callRecord struct {
+	Server string `json:"server"`
+	Tool   string `json:"tool"`
+	mcpclient.CallToolResult
+}
+
+func (a *App) runCall(args []string) int {
+	fs := flag.NewFlagSet("call", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	globalShort := fs.Bool("g", false, "only look at global/user scope")
+	globalLong := fs.Bool("global", false, "only look at global/user scope")
+	localShort := fs.Bool("l", false, "only look at local/project scope")
+	localLong := fs.Bool("local", false, "only look at local/project scope")
+	projectLong := fs.Bool("project", false, "only look at local/project scope")
+	clientFlag := fs.String("client", "", "comma-separated clients to look in")
+	clientShort := fs.String("c", "", "alias for --client")
+	var argFlags argList
+	fs.Var(&argFlags, "arg", "tool argument as key=value (repeatable)")
+	jsonFlag := fs.String("json", "", "tool arguments as a JSON object")
+	timeoutFlag := fs.Duration("timeout", defaultServerTimeout, "timeout for starting the server and running the tool")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printCallHelp()
+		return 0
+	}
+	if len(positionals) != 2 {
+		fmt.Fprintln(a.errOut, "call requires a server name and a tool name")
+		return 2
+	}
+	if *timeoutFlag <= 0 {
+		fmt.Fprintln(a.errOut, "--timeout must be positive")
+		return 2
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	arguments := map[string]any{}
+	if strings.TrimSpace(*jsonFlag) != "" {
+		if err := json.Unmarshal([]byte(*jsonFlag), &arguments); err != nil {
+			fmt.Fprintf(a.errOut, "invalid --json: %v\n", err)
+			return 2
+		}
+	}
+	for _, arg := range argFlags {
+		if key, _, ok := strings.Cut(arg, "="); !ok || strings.TrimSpace(key) == "" {
+			fmt.Fprintf(a.errOut, "invalid --arg %q: expected key=value\n", arg)
+			return 2
+		}
+	}
+
+	def, _, code := a.lookupDefinition("call", positionals[0], *clientFlag, *clientShort, *globalShort || *globalLong, *localShort || *localLong || *projectLong)
+	if code != 0 {
+		return code
+	}
+	def, code = a.unlockDefinition("call", def)
+	if code != 0 {
+		return code
+	}
+	toolName := positionals[1]
+
+	var result mcpclient.CallToolResult
+	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		client, init, err := openSession(ctx, def)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		var schema json.RawMessage
+		if init.Capabilities.Tools != nil {
+			tools, err := client.ListTools(ctx)
+			if err != nil {
+				return err
+			}
+			tool, ok := findTool(tools, toolName)
+			if !ok {
+				return fmt.Errorf("%s has no tool named %s (available: %s)", def.Name, toolName, toolNames(tools))
+			}
+			schema = tool.InputSchema
+		}
+		for _, arg := range argFlags {
+			key, value, _ := strings.Cut(arg, "=")
+			key = strings.TrimSpace(key)
+			parsed, err := parseToolArgument(value, schemaPropertyType(schema, key))
+			if err != nil {
+				return fmt.Errorf("argument %s: %w", key, err)
+			}
+			arguments[key] = parsed
+		}
+		result, err = client.CallTool(ctx, toolName, arguments)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(a.errOut, "call failed: %v\n", err)
+		return 1
+	}
+
+	code = 0
+	if result.IsError {
+		code = 1
+	}
+	if format.Structured() {
+		if result.Content == nil {
+			result.Content = []mcpclient.Content{}
+		}
+		record := callRecord{Server: def.Name, Tool: toolName, CallToolResult: result}
+		if writeCode := a.writeOutput(format, "mcp.call", []callRecord{record}); writeCode != 0 {
+			return writeCode
+		}
+		return code
+	}
+	a.printCallResult(result)
+	if result.IsError {
+		fmt.Fprintf(a.errOut, "call failed: %s reported an error\n", toolName)
+	}
+	return code
+}
+
+func schemaPropertyType(schema json.RawMessage, key string) string {
+	if len(schema) == 0 {
+		return ""
+	}
+	var parsed struct {
+		Properties map[string]struct {
+			Type json.RawMessage `json:"type"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return ""
+	}
+	property, ok := parsed.Properties[key]
+	if !ok {
+		return ""
+	}
+	var single string
+	if err := json.Unmarshal(property.Type, &single); err == nil {
+		return single
+	}
+	var several []string
+	if err := json.Unmarshal(property.Type, &several); err == nil {
+		for _, item := range several {
+			if item != "null" {
+				return item
+			}
+		}
+	}
+	return ""
+}
+
+func parseToolArgument(value, schemaType string) (any, error) {
+	switch schemaType {
+	case "string":
+		return value, nil
+	case "integer":
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", value)
+		}
+		return parsed, nil
+	case "number":
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return parsed, nil
+	case "boolean":
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", value)
+		}
+		return parsed, nil
+	case "array", "object":
+		var parsed any
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, fmt.Errorf("expected JSON %s: %v", schemaType, err)
+		}
+		return parsed, nil
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+		return parsed, nil
+	}
+	return value, nil
+}
+
+func (a *App) printCallResult(result mcpclient.CallToolResult) {
+	for idx, content := range result.Content {
+		if idx > 0 {
+			fmt.Fprintln(a.out)
+		}
+		switch content.Type {
+		case "text":
+			fmt.Fprintln(a.out, strings.TrimRight(content.Text, "\n"))
+		case "image", "audio":
+			size := len(content.Data)
+			if decoded, err := base64.StdEncoding.DecodeString(content.Data); err == nil {
+				size = len(decoded)
+			}
+			fmt.Fprintf(a.out, "[%s %s, %d bytes]\n", content.Type, content.MimeType, size)
+		case "resource_link":
+			fmt.Fprintf(a.out, "[resource link %s]\n", content.URI)
+		case "resource":
+			var resource struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			}
+			_ = json.Unmarshal(content.Resource, &resource)
+			fmt.Fprintf(a.out, "[resource %s]\n", resource.URI)
+			if resource.Text != "" {
+				fmt.Fprintln(a.out, strings.TrimRight(resource.Text, "\n"))
+			}
+		default:
+			fmt.Fprintf(a.out, "[%s content]\n", content.Type)
+		}
+	}
+	if len(result.StructuredContent) > 0 {
+		if len(result.Content) > 0 {
+			fmt.Fprintln(a.out)
+		}
+		fmt.Fprintln(a.out, "structured content:")
+		fmt.Fprint(a.out, indentJSON(result.StructuredContent, "  "))
+	}
+}
+
+func (a *App) printCallHelp() {
+	fmt.Fprintf(a.out, `Usage: %s call <name|file> <tool> [--arg key=value ...] [--json '{...}'] [--client|-c <list>] [--global|-g|--local|-l] [--timeout 30s] [--output|-o json|yaml]
+
+What it does:
+  - Starts or connects to the server and invokes one tool via MCP tools/call
+  - --arg values are converted using the tool's input schema (string, integer, number, boolean, array, object); without a schema, JSON literals are parsed and everything else is sent as a string
+  - --json passes the whole argument object; --arg entries override its keys
+  - Prints text content, summarizes images/audio/resources and pretty-prints structured content
+  - Exits 1 when the call fails or the tool reports an error
+
+Examples:
+  %s call github search_repositories --arg query=mcp --arg perPage=5
+  %s call filesystem read_file --arg path=README.md -c claude -g
+  %s call ./my-server.json echo --json '{"text":"hi"}' -o json
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
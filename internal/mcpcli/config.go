@@ -0,0 +1,329 @@
+package mcpcli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/secrets"
+)
+
+type configRecord struct {
+	Server string `json:"server"`
+	Scope  string `json:"scope"`
+	Input  string `json:"input"`
+	Value  string `json:"value,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+func (a *App) runConfig(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		a.printConfigHelp()
+		return 0
+	}
+	action := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	globalShort := fs.Bool("g", false, "use inputs saved for the global/user scope")
+	globalLong := fs.Bool("global", false, "use inputs saved for the global/user scope")
+	localShort := fs.Bool("l", false, "use inputs saved for this project (default)")
+	localLong := fs.Bool("local", false, "use inputs saved for this project (default)")
+	projectLong := fs.Bool("project", false, "use inputs saved for this project (default)")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printConfigHelp()
+		return 0
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	scope, err := resolveScope("", *globalShort || *globalLong, *localShort || *localLong || *projectLong)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid scope: %v\n", err)
+		return 2
+	}
+
+	switch action {
+	case "get":
+		if len(positionals) < 1 || len(positionals) > 2 {
+			fmt.Fprintln(a.errOut, "config get requires a server name and an optional input name")
+			return 2
+		}
+	case "set":
+		if len(positionals) < 2 || len(positionals) > 3 {
+			fmt.Fprintln(a.errOut, "config set requires a server name, an input name and a value")
+			return 2
+		}
+	case "unset":
+		if len(positionals) != 2 {
+			fmt.Fprintln(a.errOut, "config unset requires a server name and an input name")
+			return 2
+		}
+	case "":
+		fmt.Fprintln(a.errOut, "config requires an action: get, set or unset")
+		return 2
+	default:
+		fmt.Fprintf(a.errOut, "unknown config command: %s\n", action)
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	switch action {
+	case "set":
+		value, hasValue := "", len(positionals) == 3
+		if hasValue {
+			value = positionals[2]
+		}
+		return a.configSet(positionals[0], positionals[1], value, hasValue, scope, cwd)
+	case "unset":
+		server, err := configServerName(positionals[0])
+		if err != nil {
+			fmt.Fprintf(a.errOut, "config unset failed: %v\n", err)
+			return 1
+		}
+		return a.configUnset(server, positionals[1], scope, cwd)
+	}
+
+	server, err := configServerName(positionals[0])
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config get failed: %v\n", err)
+		return 1
+	}
+	saved, _, err := mcp.LoadSavedInputs(server, scope, cwd)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config get failed: %v\n", err)
+		return 1
+	}
+	if len(positionals) == 2 {
+		input := positionals[1]
+		item, ok := saved.Inputs[input]
+		if !ok {
+			fmt.Fprintf(a.errOut, "config get failed: no saved value for %s %s (%s scope)\n", server, input, scope)
+			return 1
+		}
+		value := item.Value
+		if item.Secret != "" {
+			store, err := openSecretStore(bufio.NewReader(os.Stdin), bufio.NewWriter(a.errOut))
+			if err != nil {
+				fmt.Fprintf(a.errOut, "config get failed: %v\n", err)
+				return 1
+			}
+			stored, ok, err := store.Get(item.Secret)
+			if err != nil {
+				fmt.Fprintf(a.errOut, "config get failed: %v\n", err)
+				return 1
+			}
+			if !ok {
+				fmt.Fprintf(a.errOut, "config get failed: %s is not stored in the secrets vault\n", item.Secret)
+				return 1
+			}
+			value = stored
+		}
+		if format.Structured() {
+			return a.writeOutput(format, "mcp.config", []configRecord{{Server: server, Scope: scope, Input: input, Value: value, Secret: item.Secret}})
+		}
+		fmt.Fprintln(a.out, value)
+		return 0
+	}
+
+	names := make([]string, 0, len(saved.Inputs))
+	for name := range saved.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if format.Structured() {
+		records := make([]configRecord, 0, len(names))
+		for _, name := range names {
+			item := saved.Inputs[name]
+			records = append(records, configRecord{Server: server, Scope: scope, Input: name, Value: item.Value, Secret: item.Secret})
+		}
+		return a.writeOutput(format, "mcp.config", records)
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(a.out, "no saved inputs for %s (%s scope)\n", server, scope)
+		return cli.ExitNothingToDo
+	}
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "INPUT\tVALUE")
+	for _, name := range names {
+		item := saved.Inputs[name]
+		value := item.Value
+		if item.Secret != "" {
+			value = "<secret " + item.Secret + ">"
+		} else if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", name, value)
+	}
+	writer.Flush()
+	return 0
+}
+
+func (a *App) configSet(server, inputName, value string, hasValue bool, scope, cwd string) int {
+	if err := registryindex.EnsureIndexes(); err != nil {
+		fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+		return 1
+	}
+	entry, ok, err := registryindex.FindMCP(server)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+		return 1
+	}
+	if !ok {
+		fmt.Fprintf(a.errOut, "config set failed: server not found in registry: %s\n", server)
+		return 1
+	}
+	if err := checkUnknownInputs(entry, map[string]string{inputName: value}); err != nil {
+		fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+		return 2
+	}
+	var input registryindex.MCPInput
+	for _, candidate := range entry.Inputs {
+		if strings.TrimSpace(candidate.Name) == inputName {
+			input = candidate
+		}
+	}
+
+	var store secrets.Store
+	if input.Secret {
+		reader := bufio.NewReader(os.Stdin)
+		prompts := bufio.NewWriter(a.errOut)
+		if store, err = openSecretStore(reader, prompts); err != nil {
+			fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+			return 1
+		}
+		if !hasValue {
+			if value, err = readSecretValue(reader, prompts, inputName); err != nil {
+				fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+				return 1
+			}
+		}
+	} else if !hasValue {
+		fmt.Fprintln(a.errOut, "config set requires a value for non-secret inputs")
+		return 2
+	}
+	value, err = normalizeInputValue(input, strings.TrimSpace(value))
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config set failed: input %s: %v\n", inputName, err)
+		return 2
+	}
+	if value == "" && input.Required {
+		fmt.Fprintf(a.errOut, "config set failed: input %s is required and cannot be empty\n", inputName)
+		return 2
+	}
+
+	saved := mcp.SavedInput{Value: value}
+	if input.Secret && value != "" {
+		envName := secretEnvName(entry, input)
+		if err := secrets.ValidateName(envName); err != nil {
+			fmt.Fprintf(a.errOut, "config set failed: invalid input %s: %v\n", inputName, err)
+			return 1
+		}
+		if err := store.Set(envName, value); err != nil {
+			fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+			return 1
+		}
+		saved = mcp.SavedInput{Secret: envName}
+	}
+	err = mcp.UpdateSavedInputs(entry.Name, scope, cwd, func(inputs map[string]mcp.SavedInput) error {
+		inputs[inputName] = saved
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config set failed: %v\n", err)
+		return 1
+	}
+	if saved.Secret != "" {
+		fmt.Fprintf(a.out, "saved %s for %s (%s scope) as %s in %s\n", inputName, entry.Name, scope, saved.Secret, store.Location())
+		fmt.Fprintf(a.out, "clients read it from the environment; re-export it with eval \"$(%s secrets env)\"\n", a.binaryName)
+		return 0
+	}
+	fmt.Fprintf(a.out, "saved %s for %s (%s scope)\n", inputName, entry.Name, scope)
+	fmt.Fprintf(a.out, "run \"%s install %s --force\" to apply it to installed clients\n", a.binaryName, server)
+	return 0
+}
+
+func configServerName(server string) (string, error) {
+	if err := registryindex.EnsureIndexes(); err == nil {
+		entry, ok, err := registryindex.FindMCP(server)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return entry.Name, nil
+		}
+	}
+	_, name := registryindex.SplitReference(server)
+	return name, nil
+}
+
+func (a *App) configUnset(server, inputName, scope, cwd string) int {
+	var removed mcp.SavedInput
+	found := false
+	err := mcp.UpdateSavedInputs(server, scope, cwd, func(inputs map[string]mcp.SavedInput) error {
+		removed, found = inputs[inputName]
+		delete(inputs, inputName)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(a.errOut, "config unset failed: %v\n", err)
+		return 1
+	}
+	if !found {
+		fmt.Fprintf(a.errOut, "config unset failed: no saved value for %s %s (%s scope)\n", server, inputName, scope)
+		return 1
+	}
+	fmt.Fprintf(a.out, "removed %s for %s (%s scope); the next install will ask for it again\n", inputName, server, scope)
+	if removed.Secret != "" {
+		fmt.Fprintf(a.out, "%s stays in the secrets vault; remove it with \"%s secrets rm %s\"\n", removed.Secret, a.binaryName, removed.Secret)
+	}
+	return 0
+}
+
+func (a *App) printConfigHelp() {
+	fmt.Fprintf(a.out, `Usage: %s config get <server> [input] [--global|-g] [--local|-l] [--output|-o json|yaml]
+       %s config set <server> <input> [value] [--global|-g] [--local|-l]
+       %s config unset <server> <input> [--global|-g] [--local|-l]
+
+What it does:
+  - Registry installs remember the answered inputs per server and scope in
+    ~/.mcp-skill/inputs/<server>.json; project scope answers are kept per project directory
+  - install, update and sync reuse the saved answers instead of prompting again; --input,
+    --inputs-file and MCP_INPUT_<NAME> still take precedence
+  - Secret inputs are saved as a reference to their secrets vault entry, never in plaintext
+  - get: lists the saved inputs (secrets masked), or prints one value (secrets read from the vault)
+  - set: validates the value against the registry entry; secret values are read from a hidden
+    prompt or stdin when omitted
+  - unset: forgets an answer so the next install asks again
+  - Scope defaults to the project (like install); use -g for inputs saved with global installs
+
+Examples:
+  %s config get github -g
+  %s config get github toolsets
+  %s config set github toolsets repos,issues
+  printf '%%s' "$TOKEN" | %s config set github token -g
+  %s config unset github token
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
@@ -0,0 +1,252 @@
+package mcpcli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"mcp-skill-manager/internal/doctor"
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/registryindex"
+)
+
+func (a *App) runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	fixFlag := fs.Bool("fix", false, "apply safe fixes")
+	clientFlag := fs.String("client", "", "comma-separated clients to check")
+	clientShort := fs.String("c", "", "alias for --client")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printDoctorHelp()
+		return 0
+	}
+	if len(positionals) > 0 {
+		fmt.Fprintln(a.errOut, "doctor does not accept arguments")
+		return 2
+	}
+
+	clientValue, err := resolveListClientValue(*clientFlag, *clientShort, "")
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
+	clients, err := installer.ParseTools(clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
+		return 2
+	}
+	clients, err = normalizeMcpClients(clients, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	report := &doctor.Report{}
+	installed := a.checkClientConfigs(report, clients, cwd)
+	a.checkServers(report, installed, cwd)
+	checkRequires(report, installed)
+	doctor.CheckLocalRecords(report, "mcp")
+	doctor.CheckIndex(report, "mcp")
+	return report.Finish(a.out, *fixFlag)
+}
+
+func (a *App) checkClientConfigs(report *doctor.Report, clients []installer.Tool, cwd string) []mcp.Installed {
+	var installed []mcp.Installed
+	for _, client := range clients {
+		adapter, err := mcp.AdapterFor(client)
+		if err != nil {
+			continue
+		}
+		for _, scope := range []string{installer.ScopeUser, installer.ScopeProject} {
+			if !adapter.SupportsScope(scope) {
+				continue
+			}
+			path, err := adapter.ConfigPath(scope, cwd)
+			if err != nil || !fileExists(path) {
+				continue
+			}
+			items, err := mcp.List([]string{scope}, cwd, []installer.Tool{client})
+			if err != nil {
+				report.Add(doctor.Finding{
+					Severity: doctor.SeverityError,
+					Subject:  fmt.Sprintf("%s (%s)", client, scope),
+					Problem:  fmt.Sprintf("%s does not parse: %v", path, err),
+					Fix:      a.restoreHint(client, path),
+				})
+				continue
+			}
+			report.Pass()
+			installed = append(installed, items...)
+		}
+	}
+	return installed
+}
+
+func (a *App) restoreHint(client installer.Tool, path string) string {
+	backups, err := mcp.ListBackups([]installer.Tool{client})
+	if err == nil {
+		for _, backup := range backups {
+			if backup.Path == path {
+				return fmt.Sprintf("fix the syntax by hand or run \"%s restore %s\"", a.binaryName, backup.ID)
+			}
+		}
+	}
+	return "fix the syntax by hand"
+}
+
+func (a *App) checkServers(report *doctor.Report, installed []mcp.Installed, cwd string) {
+	stores, err := registryindex.StoreDirs("mcp")
+	if err != nil {
+		return
+	}
+	reportedRepos := map[string]bool{}
+	for _, item := range installed {
+		subject := fmt.Sprintf("%s in %s (%s)", item.Name, item.Client, item.Scope)
+		def, ok, err := mcp.Read(item.Client, item.Name, item.Scope, cwd)
+		if err == nil && !ok {
+			err = fmt.Errorf("listed but not found")
+		}
+		if err != nil {
+			report.Add(doctor.Finding{Severity: doctor.SeverityError, Subject: subject, Problem: fmt.Sprintf("entry cannot be read: %v", err)})
+			continue
+		}
+		if def.Transport != "stdio" {
+			report.Pass()
+			continue
+		}
+
+		healthy := true
+		if missing := missingCommand(def.Command); missing != "" {
+			healthy = false
+			report.Add(doctor.Finding{
+				Severity: doctor.SeverityError,
+				Subject:  subject,
+				Problem:  missing,
+				Fix:      fmt.Sprintf("install %s or point the server at the right binary", filepath.Base(def.Command)),
+			})
+		}
+		for _, value := range append([]string{def.Command}, def.Args...) {
+			repo := storeRepoDir(stores, value)
+			if repo == "" || reportedRepos[repo] {
+				continue
+			}
+			if _, err := os.Stat(repo); err == nil {
+				continue
+			}
+			healthy = false
+			reportedRepos[repo] = true
+			report.Add(doctor.Finding{
+				Severity: doctor.SeverityError,
+				Subject:  subject,
+				Problem:  fmt.Sprintf("repository %s is missing", repo),
+				Fix:      fmt.Sprintf("reinstall with \"%s install %s --force -c %s %s\" to clone it again", a.binaryName, filepath.Base(repo), item.Client, scopeFlag(item.Scope)),
+			})
+		}
+		if healthy {
+			report.Pass()
+		}
+	}
+}
+
+func checkRequires(report *doctor.Report, installed []mcp.Installed) {
+	seen := map[string]bool{}
+	for _, item := range installed {
+		if seen[item.Name] {
+			continue
+		}
+		seen[item.Name] = true
+		entry, ok, err := registryindex.FindMCP(registryindex.RecordedSource("mcp", item.Name))
+		if err != nil || !ok {
+			continue
+		}
+		var missing []string
+		for _, req := range normalizeRequirements(entry.Requires, normalizeEntryType(entry)) {
+			if _, err := exec.LookPath(req); err != nil {
+				missing = append(missing, req)
+			}
+		}
+		if len(missing) == 0 {
+			report.Pass()
+			continue
+		}
+		report.Add(doctor.Finding{
+			Severity: doctor.SeverityWarning,
+			Subject:  item.Name,
+			Problem:  fmt.Sprintf("registry lists requirements that are not on PATH: %s", strings.Join(missing, ", ")),
+			Fix:      fmt.Sprintf("install %s", strings.Join(missing, ", ")),
+		})
+	}
+}
+
+func scopeFlag(scope string) string {
+	if scope == installer.ScopeUser {
+		return "-g"
+	}
+	return "-l"
+}
+
+func missingCommand(command string) string {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "stdio server has no command"
+	}
+	if strings.ContainsAny(command, `/\`) {
+		if _, err := os.Stat(command); err != nil {
+			return fmt.Sprintf("command %s does not exist", command)
+		}
+		return ""
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Sprintf("command %s is not on PATH", command)
+	}
+	return ""
+}
+
+func storeRepoDir(stores []string, value string) string {
+	if !filepath.IsAbs(value) {
+		return ""
+	}
+	for _, store := range stores {
+		rel, err := filepath.Rel(store, filepath.Clean(value))
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		name := strings.Split(rel, string(os.PathSeparator))[0]
+		if filepath.Ext(name) == ".json" {
+			return ""
+		}
+		return filepath.Join(store, name)
+	}
+	return ""
+}
+
+func (a *App) printDoctorHelp() {
+	fmt.Fprintf(a.out, `Usage: %s doctor [--client|-c <list>] [--fix]
+
+What it does:
+  - Checks that every MCP client config (user and project scope) parses
+  - Reports stdio servers whose command is not on PATH or whose cloned repo in the local store is missing
+  - Checks the tools listed under "requires" in the registry index for installed servers
+  - Finds ~/.mcp-skill/.meta/mcp records whose cached copy was deleted
+  - Warns when the registry index is missing, unreadable or older than 7 days
+  - --fix applies the safe fixes: removes stale records and re-downloads the index
+  - Exits 1 when errors remain, 0 otherwise
+
+Examples:
+  %s doctor
+  %s doctor -c claude,codex
+  %s doctor --fix
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/registryindex"
 )
 
 func resolveScope(scope string, global bool, local bool) (string, error) {
@@ -90,15 +93,28 @@ func splitArgs(args []string) ([]string, []string) {
 	var flags []string
 	var positionals []string
 	valueFlags := map[string]bool{
-		"--scope":     true,
-		"--client":    true,
-		"--tool":      true,
-		"-c":          true,
-		"--name":      true,
-		"--transport": true,
-		"--url":       true,
-		"--command":   true,
-		"--args":      true,
+		"--scope":       true,
+		"--client":      true,
+		"--tool":        true,
+		"-c":            true,
+		"--name":        true,
+		"--transport":   true,
+		"--url":         true,
+		"--command":     true,
+		"--args":        true,
+		"--output":      true,
+		"-o":            true,
+		"--file":        true,
+		"--timeout":     true,
+		"--arg":         true,
+		"--json":        true,
+		"--shell":       true,
+		"--input":       true,
+		"--inputs-file": true,
+		"--branch":      true,
+		"--priority":    true,
+		"--type":        true,
+		"--token-env":   true,
 	}
 
 	for i := 0; i < len(args); i++ {
@@ -201,22 +217,16 @@ func containsScope(scopes []string, target string) bool {
 }
 
 func normalizeMcpClients(clients []installer.Tool, clientValue string) ([]installer.Tool, error) {
-	allowed := map[installer.Tool]bool{
-		installer.ToolClaude:   true,
-		installer.ToolCodex:    true,
-		installer.ToolGemini:   true,
-		installer.ToolOpenCode: true,
-	}
 	var filtered []installer.Tool
 	var unsupported []string
 	for _, client := range clients {
-		if allowed[client] {
+		if mcp.SupportsClient(client) {
 			filtered = append(filtered, client)
 			continue
 		}
 		unsupported = append(unsupported, string(client))
 	}
-	if len(unsupported) > 0 && strings.TrimSpace(clientValue) != "" && clientValue != "all" {
+	if len(unsupported) > 0 && isExplicitClientValue(clientValue) {
 		return nil, fmt.Errorf("unsupported MCP clients: %s", strings.Join(unsupported, ", "))
 	}
 	if len(filtered) == 0 {
@@ -225,6 +235,41 @@ func normalizeMcpClients(clients []installer.Tool, clientValue string) ([]instal
 	return filtered, nil
 }
 
+func filterMcpScopes(clients []installer.Tool, scopes []string, clientValue string) ([]installer.Tool, error) {
+	var filtered []installer.Tool
+	for _, client := range clients {
+		supported := true
+		for _, scope := range scopes {
+			if mcp.SupportsScope(client, scope) {
+				continue
+			}
+			if isExplicitClientValue(clientValue) {
+				return nil, mcpScopeError(client, scope)
+			}
+			supported = false
+		}
+		if supported {
+			filtered = append(filtered, client)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no selected MCP client supports %s scope", strings.Join(scopes, "/"))
+	}
+	return filtered, nil
+}
+
+func mcpScopeError(client installer.Tool, scope string) error {
+	if scope == installer.ScopeProject {
+		return fmt.Errorf("%s MCP only supports user scope; use --global", client)
+	}
+	return fmt.Errorf("%s MCP only supports project scope; use --local", client)
+}
+
+func isExplicitClientValue(clientValue string) bool {
+	clientValue = strings.TrimSpace(clientValue)
+	return clientValue != "" && clientValue != "all"
+}
+
 func countEntries(path string) (int, error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -243,3 +288,23 @@ func fileExists(path string) bool {
 	}
 	return info.Mode().IsRegular()
 }
+
+func startDryRun(passthroughCache bool) error {
+	var passthrough []string
+	if passthroughCache {
+		paths, err := registryindex.CachePaths()
+		if err != nil {
+			return err
+		}
+		passthrough = paths
+	}
+	plan.Start(passthrough...)
+	return nil
+}
+
+func failedMessage(err error) string {
+	if registryindex.IsOffline(err) {
+		return "unavailable offline"
+	}
+	return "failed"
+}
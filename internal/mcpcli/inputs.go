@@ -0,0 +1,192 @@
+package mcpcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/secrets"
+)
+
+const inputEnvPrefix = "MCP_INPUT"
+
+func resolveInputValues(assignments []string, file string) (map[string]string, error) {
+	values := map[string]string{}
+	if strings.TrimSpace(file) != "" {
+		loaded, err := loadInputsFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range loaded {
+			values[key] = value
+		}
+	}
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --input %q: expected NAME=VALUE", assignment)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func loadInputsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	values := make(map[string]string, len(raw))
+	for key, item := range raw {
+		switch typed := item.(type) {
+		case string:
+			values[key] = typed
+		case bool:
+			values[key] = strconv.FormatBool(typed)
+		case json.Number:
+			values[key] = typed.String()
+		case nil:
+			values[key] = ""
+		default:
+			return nil, fmt.Errorf("%s: input %s must be a string, number or boolean", path, key)
+		}
+	}
+	return values, nil
+}
+
+func providedInput(name string, values map[string]string) (string, bool) {
+	if value, ok := values[name]; ok {
+		return strings.TrimSpace(value), true
+	}
+	if value, ok := os.LookupEnv(inputEnvName(name)); ok {
+		return strings.TrimSpace(value), true
+	}
+	return "", false
+}
+
+func inputEnvName(name string) string {
+	return secrets.EnvName(inputEnvPrefix, name)
+}
+
+func checkUnknownInputs(entry registryindex.MCPEntry, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	known := map[string]bool{}
+	names := make([]string, 0, len(entry.Inputs))
+	for _, input := range entry.Inputs {
+		name := strings.TrimSpace(input.Name)
+		known[name] = true
+		names = append(names, name)
+	}
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	accepted := "none"
+	if len(names) > 0 {
+		accepted = strings.Join(names, ", ")
+	}
+	return fmt.Errorf("unknown inputs for %s: %s (accepted: %s)", entry.Name, strings.Join(unknown, ", "), accepted)
+}
+
+func normalizeInputValue(input registryindex.MCPInput, value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(input.Type)) {
+	case "choice":
+		if len(input.Options) == 0 || value == "" {
+			return value, nil
+		}
+		if idx, err := strconv.Atoi(value); err == nil && idx >= 1 && idx <= len(input.Options) {
+			return input.Options[idx-1], nil
+		}
+		if isOptionMatch(input.Options, value) {
+			return value, nil
+		}
+		return "", fmt.Errorf("invalid choice %q (expected one of %s)", value, strings.Join(input.Options, ", "))
+	case "bool":
+		if value == "" && !input.Required {
+			return "", nil
+		}
+		switch strings.ToLower(value) {
+		case "y", "yes", "true", "1":
+			return "true", nil
+		case "n", "no", "false", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("invalid boolean %q (expected y/n, yes/no, true/false or 1/0)", value)
+	default:
+		return value, nil
+	}
+}
+
+func describeMissingInput(name, label, note string) string {
+	details := []string{inputEnvName(name)}
+	if label != "" && label != name {
+		details = append([]string{label}, details...)
+	}
+	if note != "" {
+		details = append(details, note)
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
+
+func (a *App) applySavedInputs(command string, def mcp.Definition, scope, cwd string, clients []installer.Tool) (mcp.Definition, map[string]string, int) {
+	saved, ok, err := mcp.LoadSavedInputs(def.Name, scope, cwd)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s failed: %v\n", command, err)
+		return mcp.Definition{}, nil, 1
+	}
+	if !ok {
+		return def, nil, 0
+	}
+	values := make(map[string]string, len(saved.Inputs))
+	for name, input := range saved.Inputs {
+		values[name] = input.Value
+		if input.Secret != "" {
+			values[name] = mcp.SecretReference(input.Secret)
+		}
+	}
+	def.URL = expandPlaceholders(def.URL, values)
+	def.Command = expandPlaceholders(def.Command, values)
+	def.Args = expandSlice(def.Args, values)
+	def.Env = expandMap(def.Env, values)
+	def.Headers = expandMap(def.Headers, values)
+
+	var unsupported []string
+	for _, client := range clients {
+		for _, name := range mcp.UnsupportedReferences(client, def) {
+			if !slices.Contains(unsupported, name) {
+				unsupported = append(unsupported, name)
+			}
+		}
+	}
+	if len(unsupported) == 0 {
+		return def, nil, 0
+	}
+	secretValues, code := a.lookupSecrets(command, unsupported)
+	if code != 0 {
+		return mcp.Definition{}, nil, code
+	}
+	return def, secretValues, 0
+}
@@ -7,6 +7,7 @@ import (
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
 	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/registryindex"
 	"os"
 	"strings"
@@ -23,9 +24,11 @@ func (a *App) runInstall(args []string) int {
 	projectLong := fs.Bool("project", false, "install to project/local scope")
 	forceShort := fs.Bool("f", false, "overwrite existing servers")
 	forceLong := fs.Bool("force", false, "overwrite existing servers")
+	frozenFlag := fs.Bool("frozen", false, "refuse registry servers whose head differs from the lockfile")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing anything")
 	allShort := fs.Bool("a", false, "install for all clients")
 	allLong := fs.Bool("all", false, "install for all clients")
-	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode")
+	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,windsurf,roo,kilocode,copilot,goose")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
 	nameFlag := fs.String("name", "", "server name (for inline definition)")
@@ -33,6 +36,14 @@ func (a *App) runInstall(args []string) int {
 	urlFlag := fs.String("url", "", "server URL for http transport")
 	commandFlag := fs.String("command", "", "command for stdio transport")
 	argsFlag := fs.String("args", "", "comma-separated args for stdio transport")
+	var inputFlags argList
+	fs.Var(&inputFlags, "input", "registry input as NAME=VALUE (repeatable)")
+	inputsFile := fs.String("inputs-file", "", "JSON file with registry input values")
+	nonInteractive := fs.Bool("non-interactive", false, "never prompt; fail when required inputs are missing")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -45,25 +56,39 @@ func (a *App) runInstall(args []string) int {
 		a.printInstallHelp()
 		return 0
 	}
-
-	clientValue, err := resolveClientValue(*clientFlag, *clientShort, *toolFlag, *allShort || *allLong)
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
 	if err != nil {
-		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
 		return 2
 	}
-	clients, err := installer.ParseTools(clientValue)
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
+	promptOut := a.out
+	if format.Structured() {
+		promptOut = a.errOut
+	}
+	inputValues, err := resolveInputValues(inputFlags, *inputsFile)
 	if err != nil {
-		fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
+		fmt.Fprintf(a.errOut, "invalid inputs: %v\n", err)
 		return 2
 	}
-	clients, err = normalizeMcpClients(clients, clientValue)
+
+	clientValue, err := resolveClientValue(*clientFlag, *clientShort, *toolFlag, *allShort || *allLong)
 	if err != nil {
 		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
 		return 2
 	}
-	clients, err = normalizeMcpClients(clients, clientValue)
+	clients, err := installer.ParseTools(clientValue)
 	if err != nil {
-		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
 		return 2
 	}
 	clients, err = normalizeMcpClients(clients, clientValue)
@@ -77,21 +102,30 @@ func (a *App) runInstall(args []string) int {
 		fmt.Fprintf(a.errOut, "invalid scope: %v\n", err)
 		return 2
 	}
-	if normalizedScope == installer.ScopeProject && containsClient(clients, installer.ToolCodex) {
-		fmt.Fprintln(a.errOut, "codex MCP only supports user scope; use --global")
-		return 2
-	}
-	if normalizedScope == installer.ScopeProject && containsClient(clients, installer.ToolCodex) {
-		fmt.Fprintln(a.errOut, "codex MCP only supports user scope; use --global")
+	clients, err = filterMcpScopes(clients, []string{normalizedScope}, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
 		return 2
 	}
 
 	var def mcp.Definition
 	var records []mcp.Installed
+	fromLocalStore := false
 	cwd, _ := os.Getwd()
 	force := *forceShort || *forceLong
+	if *dryRun {
+		if err := startDryRun(true); err != nil {
+			fmt.Fprintf(a.errOut, "install failed: %v\n", err)
+			return 1
+		}
+		defer plan.Stop()
+	}
 
 	if usesInlineDefinition(*nameFlag, *transportFlag, *urlFlag, *commandFlag, *argsFlag) {
+		if len(inputValues) > 0 {
+			fmt.Fprintln(a.errOut, "--input and --inputs-file only apply to registry servers")
+			return 2
+		}
 		args := splitArgsCSV(*argsFlag)
 		def, err = mcp.DefinitionFromArgs(*nameFlag, *transportFlag, *urlFlag, *commandFlag, args)
 		if err != nil {
@@ -111,7 +145,12 @@ func (a *App) runInstall(args []string) int {
 				return 1
 			}
 		} else {
-			if err := registryindex.EnsureIndexes(); err == nil {
+			indexErr := registryindex.EnsureIndexes()
+			if indexErr != nil && *frozenFlag {
+				fmt.Fprintf(a.errOut, "install failed: %v\n", indexErr)
+				return 1
+			}
+			if indexErr == nil {
 				entry, ok, err := registryindex.FindMCP(source)
 				if err != nil {
 					fmt.Fprintf(a.errOut, "install failed: %v\n", err)
@@ -119,29 +158,48 @@ func (a *App) runInstall(args []string) int {
 				}
 				if ok {
 					records, err = installFromRegistryEntry(entry, registryInstallOptions{
-						Scope:      normalizedScope,
-						Cwd:        cwd,
-						Clients:    clients,
-						Force:      force,
-						Out:        bufio.NewWriter(a.out),
-						ErrOut:     bufio.NewWriter(a.errOut),
-						SpinnerOut: a.errOut,
+						Scope:          normalizedScope,
+						Cwd:            cwd,
+						Clients:        clients,
+						Force:          force,
+						Frozen:         *frozenFlag,
+						Inputs:         inputValues,
+						NonInteractive: *nonInteractive,
+						Out:            bufio.NewWriter(promptOut),
+						ErrOut:         bufio.NewWriter(a.errOut),
+						SpinnerOut:     a.errOut,
 					})
 					if err != nil {
 						fmt.Fprintf(a.errOut, "install failed: %v\n", err)
 						return 1
 					}
+					if *dryRun {
+						cli.PrintPlan(a.out, plan.Stop())
+						return 0
+					}
+					if format.Structured() {
+						return a.writeOutput(format, "mcp.install", installedResults(records, "installed"))
+					}
 					for _, record := range records {
 						fmt.Fprintf(a.out, "installed %s -> %s (%s)\n", record.Name, record.Path, record.Client)
 					}
 					return 0
 				}
 			}
+			if *frozenFlag {
+				fmt.Fprintf(a.errOut, "install failed: server not found in registry: %s\n", source)
+				return 1
+			}
 			def, err = mcp.LoadLocalDefinition(source)
 			if err != nil {
 				fmt.Fprintf(a.errOut, "install failed: server not found in registry or local store: %s\n", source)
 				return 1
 			}
+			fromLocalStore = true
+		}
+		if len(inputValues) > 0 {
+			fmt.Fprintln(a.errOut, "--input and --inputs-file only apply to registry servers")
+			return 2
 		}
 	}
 
@@ -149,20 +207,42 @@ func (a *App) runInstall(args []string) int {
 		fmt.Fprintf(a.errOut, "install failed: %v\n", err)
 		return 1
 	}
+	var secretValues map[string]string
+	if fromLocalStore {
+		var code int
+		if def, secretValues, code = a.applySavedInputs("install", def, normalizedScope, cwd, clients); code != 0 {
+			return code
+		}
+	}
+	installOpts := registryInstallOptions{
+		Scope:   normalizedScope,
+		Cwd:     cwd,
+		Clients: clients,
+		Force:   force,
+		ErrOut:  bufio.NewWriter(a.errOut),
+	}
 
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		var installErr error
-		records, installErr = mcp.Install(def, normalizedScope, cwd, clients, force)
+		records, installErr = installWithSecretReferences(def, secretValues, installOpts)
 		return installErr
 	})
+	if err != nil && !force && isAlreadyExistsError(err) && *nonInteractive {
+		fmt.Fprintf(a.errOut, "install failed: %v (use --force to overwrite)\n", err)
+		return 1
+	}
 	if err != nil && !force && isAlreadyExistsError(err) {
-		if !confirmPrompt(a.out, "Server already exists. Overwrite? Type 'yes' to continue: ") {
-			fmt.Fprintln(a.out, "canceled")
+		if !*dryRun && !confirmPrompt(promptOut, "Server already exists. Overwrite? Type 'yes' to continue: ") {
+			fmt.Fprintln(promptOut, "canceled")
+			if format.Structured() {
+				return a.writeOutput(format, "mcp.install", nil)
+			}
 			return 0
 		}
+		installOpts.Force = true
 		err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 			var installErr error
-			records, installErr = mcp.Install(def, normalizedScope, cwd, clients, true)
+			records, installErr = installWithSecretReferences(def, secretValues, installOpts)
 			return installErr
 		})
 	}
@@ -170,6 +250,13 @@ func (a *App) runInstall(args []string) int {
 		fmt.Fprintf(a.errOut, "install failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+		return 0
+	}
+	if format.Structured() {
+		return a.writeOutput(format, "mcp.install", installedResults(records, "installed"))
+	}
 
 	for _, record := range records {
 		fmt.Fprintf(a.out, "installed %s -> %s (%s)\n", record.Name, record.Path, record.Client)
@@ -178,19 +265,44 @@ func (a *App) runInstall(args []string) int {
 }
 
 func (a *App) printInstallHelp() {
-	fmt.Fprintf(a.out, `Usage: %s install <name|path> [--global|-g] [--local|-l] [--force|-f] [--client|-c <list>] [--all|-a]
-       %s install --name <name> --transport <http|stdio> [--url <url> | --command <cmd>] [--args <a,b>] [--client|-c <list>] [--all|-a]
+	fmt.Fprintf(a.out, `Usage: %s install <name|path> [--global|-g] [--local|-l] [--force|-f] [--frozen] [--dry-run] [--output|-o <fmt>] [--client|-c <list>] [--all|-a] [--offline] [--refresh]
+       %s install <name> [--input NAME=VALUE ...] [--inputs-file <json>] [--non-interactive]
+       %s install --name <name> --transport <http|stdio> [--url <url> | --command <cmd>] [--args <a,b>] [--dry-run] [--client|-c <list>] [--all|-a]
 
 What it does:
   - Registry name: checks requirements, prompts for inputs, clones/builds if needed, then writes config
+  - Answers are saved per server and scope and reused by later installs and updates instead
+    of prompting again (see "%s config -h")
+  - Secret inputs go to the secrets vault and the config references an environment variable
+    (see "%s secrets -h")
+  - Inputs can be supplied without prompts: --input NAME=VALUE (repeatable), --inputs-file
+    with a JSON object, or MCP_INPUT_<NAME> variables (in that order of precedence, all ahead
+    of saved answers); choice and bool values are validated like the prompt
+  - With --non-interactive: never prompts; missing required inputs are listed and the
+    install fails, existing servers need --force and cached repos are not refreshed
+    (secret inputs are stored in the OS keyring, or in the file vault when
+    MCP_SKILL_VAULT_PASSPHRASE is set; it never prompts for the passphrase)
   - File path: loads the MCP definition JSON and writes config
+  - Local store name (registry unavailable): fills the stored template from the saved answers
   - Inline definition: uses flags to build a definition and writes config
+  - Registry installs record head, updatedAt, repo and entry hash in .mcp-skill.lock.json
+    (next to .mcp-skill.json, or in ~/.mcp-skill); --frozen refuses entries that differ
+  - With --dry-run: prints the files that would change (with a diff) and the clone/build
+    commands that would run, without writing anything; it never prompts or stores secrets,
+    and required inputs without a value are shown as <NAME> placeholders
+  - With --output json|yaml: prints the installed records; prompts go to stderr
+  - With --offline (or MCP_SKILL_OFFLINE=1): never touches the network or git; uses the cached
+    registry index, stdio servers need their repository in ~/.mcp-skill/mcp and install steps
+    are skipped
 
 Examples:
   %s install github -c claude
   %s install D:\mcp\github.json -c codex
   %s install --name github --transport http --url https://example.com/mcp -c claude
-`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+  %s install github -g -a --dry-run
+  %s install github -c claude --non-interactive --input token=$GITHUB_TOKEN
+  MCP_INPUT_TOKEN=... %s install github -c claude --non-interactive
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
 
 func usesInlineDefinition(name, transport, url, command, args string) bool {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"mcp-skill-manager/internal/cli"
@@ -23,9 +24,13 @@ func (a *App) runList(args []string) int {
 	projectLong := fs.Bool("project", false, "show local/project scope")
 	availableShort := fs.Bool("a", false, "show available servers in registry")
 	availableLong := fs.Bool("available", false, "show available servers in registry")
-	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode")
+	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,windsurf,roo,kilocode,copilot,goose")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -37,6 +42,12 @@ func (a *App) runList(args []string) int {
 		a.printListHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
 
 	if len(positionals) > 1 {
 		fmt.Fprintln(a.errOut, "list accepts at most one server name")
@@ -47,8 +58,14 @@ func (a *App) runList(args []string) int {
 		nameFilter = positionals[0]
 	}
 
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
 	if *availableShort || *availableLong {
-		return a.runListAvailable(nameFilter)
+		return a.runListAvailable(nameFilter, format)
 	}
 
 	clientValue, err := resolveListClientValue(*clientFlag, *clientShort, *toolFlag)
@@ -62,9 +79,16 @@ func (a *App) runList(args []string) int {
 		return 2
 	}
 
+	clients, err = normalizeMcpClients(clients, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
+
 	scopes := resolveListScopes(*globalShort || *globalLong, *localShort || *localLong || *projectLong)
-	if containsScope(scopes, installer.ScopeProject) && containsClient(clients, installer.ToolCodex) {
-		fmt.Fprintln(a.errOut, "codex MCP only supports user scope; use --global")
+	clients, err = filterMcpScopes(clients, scopes, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
 		return 2
 	}
 	cwd, _ := os.Getwd()
@@ -79,11 +103,6 @@ func (a *App) runList(args []string) int {
 		return 1
 	}
 
-	if len(items) == 0 {
-		fmt.Fprintln(a.out, "no servers installed")
-		return 0
-	}
-
 	sort.Slice(items, func(i, j int) bool {
 		if items[i].Client != items[j].Client {
 			return items[i].Client < items[j].Client
@@ -94,6 +113,21 @@ func (a *App) runList(args []string) int {
 		return items[i].Name < items[j].Name
 	})
 
+	if format.Structured() {
+		records := []mcp.Installed{}
+		for _, item := range items {
+			if matchesFilter(item.Name, nameFilter) {
+				records = append(records, item)
+			}
+		}
+		return a.writeOutput(format, "mcp.installed", records)
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintln(a.out, "no servers installed")
+		return 0
+	}
+
 	var (
 		lastClient installer.Tool
 		lastScope  string
@@ -140,15 +174,21 @@ func (a *App) runList(args []string) int {
 	return 0
 }
 
-func (a *App) runListAvailable(nameFilter string) int {
+func (a *App) runListAvailable(nameFilter string, format cli.OutputFormat) int {
 	var outputErr error
 	type row struct {
 		name        string
+		registry    string
 		typ         string
 		updatedAt   string
+		offline     string
 		description string
 	}
 	var rows []row
+	matched := []registryindex.MCPEntry{}
+	showRegistry := false
+	offline := registryindex.Offline()
+	unavailable := 0
 	err := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		if err := registryindex.EnsureIndexes(); err != nil {
 			outputErr = err
@@ -159,6 +199,12 @@ func (a *App) runListAvailable(nameFilter string) int {
 			outputErr = err
 			return err
 		}
+		registries, err := registryindex.Registries()
+		if err != nil {
+			outputErr = err
+			return err
+		}
+		showRegistry = len(registries) > 1
 		entries := index.MCP
 		if len(entries) == 0 {
 			entries = index.Servers
@@ -167,7 +213,7 @@ func (a *App) runListAvailable(nameFilter string) int {
 			return nil
 		}
 
-		sort.Slice(entries, func(i, j int) bool {
+		sort.SliceStable(entries, func(i, j int) bool {
 			return entries[i].Name < entries[j].Name
 		})
 
@@ -175,10 +221,18 @@ func (a *App) runListAvailable(nameFilter string) int {
 			if !matchesFilter(entry.Name, nameFilter) {
 				continue
 			}
+			matched = append(matched, entry)
+			status := "available"
+			if !registryindex.MCPAvailableOffline(entry) {
+				status = "unavailable"
+				unavailable++
+			}
 			rows = append(rows, row{
 				name:        entry.Name,
+				registry:    entry.Registry,
 				typ:         displayTransport(entry.Type),
 				updatedAt:   entry.UpdatedAt,
+				offline:     status,
 				description: truncateDescription(entry.Description, 80),
 			})
 		}
@@ -188,33 +242,60 @@ func (a *App) runListAvailable(nameFilter string) int {
 		fmt.Fprintf(a.errOut, "list failed: %v\n", outputErr)
 		return 1
 	}
+	if format.Structured() {
+		return a.writeOutput(format, "mcp.registry", matched)
+	}
 
 	if len(rows) == 0 {
 		fmt.Fprintln(a.out, "no matching servers found")
 		return 0
 	}
 	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
-	fmt.Fprintln(writer, "NAME\tTYPE\tUPDATED\tDESCRIPTION")
+	header := []string{"NAME"}
+	if showRegistry {
+		header = append(header, "REGISTRY")
+	}
+	header = append(header, "TYPE", "UPDATED")
+	if offline {
+		header = append(header, "OFFLINE")
+	}
+	fmt.Fprintln(writer, strings.Join(append(header, "DESCRIPTION"), "\t"))
 	for _, item := range rows {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", item.name, item.typ, item.updatedAt, item.description)
+		columns := []string{item.name}
+		if showRegistry {
+			columns = append(columns, item.registry)
+		}
+		columns = append(columns, item.typ, item.updatedAt)
+		if offline {
+			columns = append(columns, item.offline)
+		}
+		fmt.Fprintln(writer, strings.Join(append(columns, item.description), "\t"))
 	}
 	if err := writer.Flush(); err != nil {
 		fmt.Fprintf(a.errOut, "list failed: %v\n", err)
 		return 1
 	}
+	if offline && unavailable > 0 {
+		fmt.Fprintf(a.errOut, "offline: %d of %d servers need the network (their repository is not in ~/.mcp-skill/mcp)\n", unavailable, len(rows))
+	}
 	return 0
 }
 
 func (a *App) printListHelp() {
-	fmt.Fprintf(a.out, `Usage: %s list [name] [--available|-a] [--global|-g] [--local|-l] [--client|-c <list>]
+	fmt.Fprintf(a.out, `Usage: %s list [name] [--available|-a] [--global|-g] [--local|-l] [--client|-c <list>] [--output|-o <text|json|yaml>] [--offline] [--refresh]
 
 What it does:
   - Default: list installed MCP servers
   - With --available: list registry MCP servers
+  - With --offline (or MCP_SKILL_OFFLINE=1): reads the cached registry index and adds an
+    OFFLINE column telling which servers can be installed without the network
+  - With --refresh: revalidates the cached registry index now instead of after MCP_REGISTRY_TTL
+  - With --output json|yaml: print {schemaVersion, kind, items} instead of tables
 
 Examples:
   %s list
   %s list github -g -c claude
   %s list --available
-`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+  %s list -g -o json
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
@@ -0,0 +1,22 @@
+package mcpcli
+
+import (
+	"os"
+	"testing"
+
+	"mcp-skill-manager/internal/mcptest"
+)
+
+const serverModeEnv = "MCPCLI_TEST_SERVER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(serverModeEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "silent":
+		(&mcptest.Server{Silent: true}).Serve(os.Stdin, os.Stdout)
+	default:
+		(&mcptest.Server{}).Serve(os.Stdin, os.Stdout)
+	}
+	os.Exit(0)
+}
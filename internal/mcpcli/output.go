@@ -0,0 +1,44 @@
+package mcpcli
+
+import (
+	"fmt"
+	"strings"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/mcp"
+)
+
+type installedRecord struct {
+	mcp.Installed
+	Definition *mcp.Definition `json:"definition,omitempty"`
+}
+
+type resultRecord struct {
+	mcp.Installed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func resolveOutputFormat(long, short string) (cli.OutputFormat, error) {
+	value := long
+	if strings.TrimSpace(short) != "" {
+		value = short
+	}
+	return cli.ParseOutputFormat(value)
+}
+
+func (a *App) writeOutput(format cli.OutputFormat, kind string, items any) int {
+	if err := cli.WriteOutput(a.out, format, kind, items); err != nil {
+		fmt.Fprintf(a.errOut, "output failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func installedResults(records []mcp.Installed, status string) []resultRecord {
+	results := make([]resultRecord, 0, len(records))
+	for _, record := range records {
+		results = append(results, resultRecord{Installed: record, Status: status})
+	}
+	return results
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 
 	"mcp-skill-manager/internal/mcp"
@@ -12,6 +13,9 @@ import (
 
 func printMcpEntry(out io.Writer, entry registryindex.MCPEntry) {
 	fmt.Fprintf(out, "name: %s\n", entry.Name)
+	if entry.Registry != "" && entry.Registry != registryindex.DefaultRegistry {
+		fmt.Fprintf(out, "registry: %s\n", entry.Registry)
+	}
 	if entry.Type != "" {
 		fmt.Fprintf(out, "type: %s\n", entry.Type)
 	}
@@ -64,4 +68,13 @@ func printDefinition(out io.Writer, def mcp.Definition) {
 			fmt.Fprintf(out, "  %s: %s\n", key, def.Headers[key])
 		}
 	}
+	if def.StartupTimeoutSec > 0 {
+		fmt.Fprintf(out, "startup timeout: %ss\n", strconv.FormatFloat(def.StartupTimeoutSec, 'f', -1, 64))
+	}
+	if def.ToolTimeoutSec > 0 {
+		fmt.Fprintf(out, "tool timeout: %ss\n", strconv.FormatFloat(def.ToolTimeoutSec, 'f', -1, 64))
+	}
+	if def.Disabled {
+		fmt.Fprintln(out, "enabled: false")
+	}
 }
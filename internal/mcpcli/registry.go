@@ -0,0 +1,220 @@
+package mcpcli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/credentials"
+	"mcp-skill-manager/internal/registryindex"
+)
+
+type registryRecord struct {
+	registryindex.Registry
+	LastSync string `json:"lastSync,omitempty"`
+}
+
+func (a *App) runRegistry(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		a.printRegistryHelp()
+		return 0
+	}
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("registry", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	typeFlag := fs.String("type", "", "registry type: github, gitlab, gitea, http or local (detected from the URL by default)")
+	branchFlag := fs.String("branch", "", "branch to read the indexes from (default main; git hosts only)")
+	tokenEnvFlag := fs.String("token-env", "", "environment variable holding a token sent with this registry's HTTP requests")
+	priorityFlag := fs.Int("priority", 10, "priority; entries from higher-priority registries win name clashes")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printRegistryHelp()
+		return 0
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
+	switch action {
+	case "list", "ls":
+		if len(positionals) > 0 {
+			fmt.Fprintln(a.errOut, "registry list takes no arguments")
+			return 2
+		}
+		return a.registryList(format)
+	case "add":
+		if len(positionals) != 2 {
+			fmt.Fprintln(a.errOut, "registry add requires a name and a URL or path")
+			return 2
+		}
+		if err := registryindex.ValidateRegistryName(positionals[0]); err != nil {
+			fmt.Fprintf(a.errOut, "registry add failed: %v\n", err)
+			return 2
+		}
+		return a.registryAdd(registryindex.Registry{
+			Name:     positionals[0],
+			URL:      positionals[1],
+			Type:     *typeFlag,
+			Branch:   *branchFlag,
+			Priority: *priorityFlag,
+			TokenEnv: *tokenEnvFlag,
+		})
+	case "remove", "rm":
+		if len(positionals) != 1 {
+			fmt.Fprintln(a.errOut, "registry remove requires a registry name")
+			return 2
+		}
+		return a.registryRemove(positionals[0])
+	default:
+		fmt.Fprintf(a.errOut, "unknown registry command: %s\n", action)
+		return 2
+	}
+}
+
+func (a *App) registryList(format cli.OutputFormat) int {
+	registries, err := registryindex.Registries()
+	if err != nil {
+		fmt.Fprintf(a.errOut, "registry list failed: %v\n", err)
+		return 1
+	}
+	records := make([]registryRecord, 0, len(registries))
+	for _, reg := range registries {
+		record := registryRecord{Registry: reg}
+		record.URL = credentials.Redact(reg.URL)
+		record.Type = "-"
+		if kind, err := reg.SourceType(); err == nil {
+			record.Type = kind
+		}
+		if lastSync, ok, err := reg.LastSync(); err == nil && ok {
+			record.LastSync = lastSync.UTC().Format(time.RFC3339)
+		}
+		records = append(records, record)
+	}
+	if format.Structured() {
+		return a.writeOutput(format, "mcp.registries", records)
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(a.out, "no registries configured")
+		return 0
+	}
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tPRIORITY\tTYPE\tURL\tBRANCH\tLAST SYNC")
+	for _, record := range records {
+		lastSync := "never"
+		if record.LastSync != "" {
+			lastSync = record.LastSync
+		}
+		branch := record.Branch
+		if record.Type == registryindex.SourceHTTP || record.Type == registryindex.SourceLocal {
+			branch = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\n", record.Name, record.Priority, record.Type, record.URL, branch, lastSync)
+	}
+	if err := writer.Flush(); err != nil {
+		fmt.Fprintf(a.errOut, "registry list failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (a *App) registryAdd(reg registryindex.Registry) int {
+	if err := registryindex.AddRegistry(reg); err != nil {
+		fmt.Fprintf(a.errOut, "registry add failed: %v\n", err)
+		return 1
+	}
+	added, _, err := registryindex.FindRegistry(reg.Name)
+	if err == nil {
+		err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, added.Refresh)
+	}
+	if err != nil {
+		if _, removeErr := registryindex.RemoveRegistry(reg.Name); removeErr != nil {
+			fmt.Fprintf(a.errOut, "registry add failed: %v (and removing it again failed: %v)\n", err, removeErr)
+			return 1
+		}
+		fmt.Fprintf(a.errOut, "registry add failed: cannot download the indexes of %s: %v\n", credentials.Redact(reg.URL), err)
+		return 1
+	}
+	kind, _ := added.SourceType()
+	if kind == registryindex.SourceHTTP || kind == registryindex.SourceLocal {
+		fmt.Fprintf(a.out, "added registry %s (%s %s, priority %d)\n", added.Name, kind, credentials.Redact(added.URL), added.Priority)
+		return 0
+	}
+	fmt.Fprintf(a.out, "added registry %s (%s %s, branch %s, priority %d)\n", added.Name, kind, credentials.Redact(added.URL), added.Branch, added.Priority)
+	return 0
+}
+
+func (a *App) registryRemove(name string) int {
+	removed, err := registryindex.RemoveRegistry(name)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "registry remove failed: %v\n", err)
+		return 1
+	}
+	if !removed {
+		fmt.Fprintf(a.errOut, "registry remove failed: no registry named %s\n", name)
+		return 1
+	}
+	fmt.Fprintf(a.out, "removed registry %s\n", name)
+	if registries, err := registryindex.Registries(); err == nil && len(registries) == 0 {
+		fmt.Fprintf(a.out, "no registries left; add one with \"%s registry add <name> <url>\"\n", a.binaryName)
+	}
+	return 0
+}
+
+func (a *App) printRegistryHelp() {
+	fmt.Fprintf(a.out, `Usage: %s registry [list] [--output|-o json|yaml]
+       %s registry add <name> <url|path> [--type <type>] [--branch <branch>] [--priority <n>] [--token-env <VAR>]
+       %s registry remove|rm <name>
+
+What it does:
+  - A registry is a tree with index.skill.json and index.mcp.json at its root and skills under
+    skill/<name>; the list is kept in ~/.mcp-skill/registries.json and shared by %s and skill
+  - Registry types (detected from the URL, or set with --type):
+      github   github.com repositories or owner/repo (raw.githubusercontent.com, skills via git)
+      gitlab   gitlab.com or self-hosted GitLab projects (<project>/-/raw/<branch>/, skills via git)
+      gitea    gitea.com, codeberg.org or self-hosted Gitea/Forgejo (raw/branch/<branch>/, skills via git)
+      http     any other https:// base URL; skills are downloaded as skill/<name>.tar.gz
+      local    a directory or file:// URL, re-read on every command (handy for offline testing)
+  - Without a registries.json only the public "%s" registry is used (MCP_REGISTRY_REPO and
+    MCP_REGISTRY_BRANCH still override its URL and branch)
+  - Indexes are merged: when several registries list the same name, the one with the highest
+    priority wins (the default registry has priority 0, add uses 10 unless --priority is given)
+  - <registry>/<name> picks an entry from one registry, e.g. "%s install acme/github"
+  - Each registry keeps its own index cache, records, cached skills and server repositories under
+    ~/.mcp-skill/registries/<name> (the default registry keeps using ~/.mcp-skill)
+  - --token-env names an environment variable whose token is sent with the registry's HTTP
+    requests (Authorization: Bearer; PRIVATE-TOKEN for gitlab, "token" for gitea); the token
+    itself is never written to registries.json; without it, tokens come from MCP_SKILL_TOKEN_<HOST>,
+    ~/.mcp-skill/credentials.json, GITHUB_TOKEN/GH_TOKEN or GITLAB_TOKEN, for git clones too
+  - SSH URLs (git@host:owner/repo.git) clone skills over SSH and read indexes over HTTPS
+  - add downloads the indexes right away and is not saved when that fails
+  - remove deletes the registry's cached indexes; installed servers and skills stay
+
+Examples:
+  %s registry
+  %s registry add acme https://github.com/acme/mcp-registry --branch stable --priority 20
+  %s registry add work https://git.example.com/platform/registry --type gitlab
+  %s registry add mirror https://registry.example.com/mcp/
+  %s registry add private https://registry.example.com/team/ --token-env REGISTRY_TOKEN
+  %s registry add dev ./my-registry
+  %s install acme/github -c claude
+  %s registry rm acme
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, registryindex.DefaultRegistry, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
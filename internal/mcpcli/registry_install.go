@@ -9,24 +9,32 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
 	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/credentials"
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/lockfile"
 	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/safefile"
+	"mcp-skill-manager/internal/secrets"
 )
 
 type registryInstallOptions struct {
-	Scope      string
-	Cwd        string
-	Clients    []installer.Tool
-	Force      bool
-	Out        *bufio.Writer
-	ErrOut     *bufio.Writer
-	SpinnerOut io.Writer
+	Scope          string
+	Cwd            string
+	Clients        []installer.Tool
+	Force          bool
+	Frozen         bool
+	Inputs         map[string]string
+	NonInteractive bool
+	Out            *bufio.Writer
+	ErrOut         *bufio.Writer
+	SpinnerOut     io.Writer
 }
 
 func installFromRegistryEntry(entry registryindex.MCPEntry, opts registryInstallOptions) ([]mcp.Installed, error) {
@@ -34,13 +42,35 @@ func installFromRegistryEntry(entry registryindex.MCPEntry, opts registryInstall
 	if entryType == "" {
 		return nil, fmt.Errorf("invalid mcp entry: missing type")
 	}
+	if registryindex.Offline() {
+		entry = registryindex.CachedMCPEntry(entry)
+	}
+
+	lockPath, err := lockfile.Path(opts.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := entryHash(entry)
+	if err != nil {
+		return nil, err
+	}
+	lockKey := registryindex.LockKey(entry.Registry, entry.Name)
+	if opts.Frozen {
+		locked, err := lockfile.CheckFrozen(lockPath, "mcp", lockKey, entry.Head)
+		if err != nil {
+			return nil, err
+		}
+		if locked.Hash != hash {
+			return nil, fmt.Errorf("mcp %s registry entry hash %s differs from lockfile hash %s", lockKey, hash, locked.Hash)
+		}
+	}
 
 	requirements := normalizeRequirements(entry.Requires, entryType)
 	if err := checkRequirements(requirements); err != nil {
 		return nil, err
 	}
 
-	inputs, err := collectInputs(entry.Inputs, opts.Out)
+	inputs, secretValues, err := collectInputs(entry, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +80,12 @@ func installFromRegistryEntry(entry registryindex.MCPEntry, opts registryInstall
 		if strings.TrimSpace(entry.Repo) == "" {
 			return nil, fmt.Errorf("invalid mcp entry: missing repo")
 		}
-		repoPath, repoUpdated, err := ensureRepo(entry, opts)
+		var repoUpdated bool
+		repoPath, repoUpdated, err = ensureRepo(entry, opts)
 		if err != nil {
 			return nil, err
 		}
-		if repoUpdated || opts.Force {
+		if (repoUpdated || opts.Force) && !registryindex.Offline() {
 			err = cli.RunWithSpinner(opts.SpinnerOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 				return runInstallSteps(entry.Install, repoPath)
 			})
@@ -77,13 +108,17 @@ func installFromRegistryEntry(entry registryindex.MCPEntry, opts registryInstall
 		return nil, err
 	}
 
-	records, err := mcp.Install(def, opts.Scope, opts.Cwd, opts.Clients, opts.Force)
+	records, err := installWithSecretReferences(def, secretValues, opts)
 	if err != nil {
 		return nil, err
 	}
+	if err := saveInputAnswers(entry, inputs, opts); err != nil {
+		return nil, err
+	}
 
 	if err := registryindex.SaveLocalRecord("mcp", registryindex.LocalRecord{
 		Name:      entry.Name,
+		Registry:  entry.Registry,
 		Repo:      entry.Repo,
 		Path:      entry.Path,
 		Head:      entry.Head,
@@ -91,10 +126,25 @@ func installFromRegistryEntry(entry registryindex.MCPEntry, opts registryInstall
 	}); err != nil {
 		return nil, err
 	}
+	if err := lockfile.Update(lockPath, "mcp", lockKey, lockfile.Entry{
+		Repo:      entry.Repo,
+		Path:      entry.Path,
+		Head:      entry.Head,
+		UpdatedAt: entry.UpdatedAt,
+		Hash:      hash,
+	}); err != nil {
+		return nil, err
+	}
 
 	return records, nil
 }
 
+func entryHash(entry registryindex.MCPEntry) (string, error) {
+	entry.CheckedAt = ""
+	entry.Registry = ""
+	return lockfile.HashJSON(entry)
+}
+
 func normalizeEntryType(entry registryindex.MCPEntry) string {
 	entryType := strings.ToLower(strings.TrimSpace(entry.Type))
 	if entryType == "" && strings.TrimSpace(entry.URL) != "" {
@@ -139,25 +189,261 @@ func checkRequirements(requirements []string) error {
 	return nil
 }
 
-func collectInputs(inputs []registryindex.MCPInput, out *bufio.Writer) (map[string]string, error) {
-	values := make(map[string]string, len(inputs))
+func collectInputs(entry registryindex.MCPEntry, opts registryInstallOptions) (map[string]string, map[string]string, error) {
+	if err := checkUnknownInputs(entry, opts.Inputs); err != nil {
+		return nil, nil, err
+	}
+	saved, _, err := mcp.LoadSavedInputs(entry.Name, opts.Scope, opts.Cwd)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := make(map[string]string, len(entry.Inputs))
+	secretValues := map[string]string{}
 	reader := bufio.NewReader(os.Stdin)
-	for _, input := range inputs {
+	var store secrets.Store
+	var missing []string
+	var reused []string
+	var placeholders []string
+	dryRun := plan.Active()
+	interactive := !opts.NonInteractive && !dryRun
+	for _, input := range entry.Inputs {
 		name := strings.TrimSpace(input.Name)
 		if name == "" {
-			return nil, fmt.Errorf("invalid input: missing name")
+			return nil, nil, fmt.Errorf("invalid input: missing name")
 		}
 		label := strings.TrimSpace(input.Label)
 		if label == "" {
 			label = name
 		}
-		value, err := promptInput(reader, out, label, input)
+		provided, hasProvided := providedInput(name, opts.Inputs)
+		if hasProvided && provided != "" {
+			normalized, err := normalizeInputValue(input, provided)
+			if err != nil {
+				return nil, nil, fmt.Errorf("input %s: %w", name, err)
+			}
+			provided = normalized
+		}
+		remembered, isRemembered := saved.Inputs[name]
+		if isRemembered && remembered.Secret != "" && !input.Secret {
+			isRemembered = false
+		}
+		if isRemembered && remembered.Secret == "" {
+			normalized, err := normalizeInputValue(input, remembered.Value)
+			isRemembered = err == nil && (normalized != "" || !input.Required)
+			remembered.Value = normalized
+		}
+
+		var value string
+		if input.Secret {
+			envName := secretEnvName(entry, input)
+			if err := secrets.ValidateName(envName); err != nil {
+				return nil, nil, fmt.Errorf("invalid input %s: %w", name, err)
+			}
+			if store == nil {
+				var err error
+				if !interactive {
+					store, err = secrets.Open(secrets.Options{Passphrase: func(bool) (string, error) {
+						return "", fmt.Errorf("--non-interactive cannot prompt for the vault passphrase: set %s", secrets.PassphraseEnv)
+					}})
+				} else {
+					store, err = openSecretStore(reader, opts.Out)
+				}
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			stored, hasStored, err := store.Get(envName)
+			if err != nil && dryRun {
+				stored, hasStored, err = "", false, nil
+			}
+			if err != nil && opts.NonInteractive && !hasProvided && input.Required {
+				missing = append(missing, describeMissingInput(name, label, "stored value unreadable: "+err.Error()))
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			switch {
+			case hasProvided:
+				value = provided
+			case isRemembered && remembered.Secret != "" && hasStored:
+				value = stored
+				reused = append(reused, name)
+			case isRemembered && remembered.Secret == "":
+				value = remembered.Value
+				if value == "" && hasStored {
+					value = stored
+				}
+				reused = append(reused, name)
+			case !interactive && hasStored:
+				value = stored
+			case !interactive:
+				value = input.Default
+			default:
+				if value, err = promptSecret(reader, opts.Out, label, input, envName, hasStored); err != nil {
+					return nil, nil, err
+				}
+				if value == "" && hasStored {
+					value = stored
+				}
+			}
+			if !dryRun && value != "" && (!hasStored || value != stored) {
+				if err := store.Set(envName, value); err != nil {
+					return nil, nil, err
+				}
+			}
+			values[name] = ""
+			if value != "" {
+				values[name] = mcp.SecretReference(envName)
+				secretValues[envName] = value
+			}
+		} else {
+			var err error
+			switch {
+			case hasProvided:
+				value = provided
+			case isRemembered:
+				value = remembered.Value
+				reused = append(reused, name)
+			case !interactive && input.Default != "":
+				if value, err = normalizeInputValue(input, input.Default); err != nil {
+					return nil, nil, fmt.Errorf("input %s: default: %w", name, err)
+				}
+			case !interactive:
+			default:
+				if value, err = promptInput(reader, opts.Out, label, input); err != nil {
+					return nil, nil, err
+				}
+			}
+			values[name] = value
+		}
+		if value == "" && input.Required && dryRun {
+			placeholders = append(placeholders, name)
+			values[name] = inputPlaceholder(name)
+			continue
+		}
+		if value == "" && input.Required {
+			missing = append(missing, describeMissingInput(name, label, ""))
+		}
+	}
+	if len(placeholders) > 0 {
+		fmt.Fprintf(opts.Out, "dry run: no value for %s; the plan shows placeholders\n", strings.Join(placeholders, ", "))
+		if err := opts.Out.Flush(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("missing required inputs for %s: %s (set MCP_INPUT_<NAME>, or pass --input NAME=VALUE / --inputs-file to install)", entry.Name, strings.Join(missing, ", "))
+	}
+	if len(reused) > 0 {
+		fmt.Fprintf(opts.Out, "using saved inputs for %s: %s (change them with \"mcp config set %s <input>\")\n", entry.Name, strings.Join(reused, ", "), entry.Name)
+		if err := opts.Out.Flush(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return values, secretValues, nil
+}
+
+func inputPlaceholder(name string) string {
+	return "<" + name + ">"
+}
+
+func saveInputAnswers(entry registryindex.MCPEntry, values map[string]string, opts registryInstallOptions) error {
+	if len(entry.Inputs) == 0 {
+		return nil
+	}
+	return mcp.UpdateSavedInputs(entry.Name, opts.Scope, opts.Cwd, func(saved map[string]mcp.SavedInput) error {
+		for key := range saved {
+			delete(saved, key)
+		}
+		for _, input := range entry.Inputs {
+			name := strings.TrimSpace(input.Name)
+			value, ok := values[name]
+			if !ok || value == inputPlaceholder(name) {
+				continue
+			}
+			if input.Secret && value != "" {
+				saved[name] = mcp.SavedInput{Secret: secretEnvName(entry, input)}
+				continue
+			}
+			saved[name] = mcp.SavedInput{Value: value}
+		}
+		return nil
+	})
+}
+
+func secretEnvName(entry registryindex.MCPEntry, input registryindex.MCPInput) string {
+	if env := strings.TrimSpace(input.Env); env != "" {
+		return env
+	}
+	placeholder := "${" + strings.TrimSpace(input.Name) + "}"
+	var keys []string
+	for key, value := range entry.Run.Env {
+		if strings.TrimSpace(value) == placeholder {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 1 && secrets.ValidateName(keys[0]) == nil {
+		return keys[0]
+	}
+	return secrets.EnvName(entry.Name, input.Name)
+}
+
+func promptSecret(reader *bufio.Reader, out *bufio.Writer, label string, input registryindex.MCPInput, envName string, hasStored bool) (string, error) {
+	for {
+		prompt := fmt.Sprintf("%s (secret, saved as %s)", label, envName)
+		if hasStored {
+			prompt += " [keep stored value]"
+		}
+		fmt.Fprintf(out, "%s: ", prompt)
+		value, err := readHiddenLine(reader, out)
+		if err != nil {
+			return "", err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" && !hasStored {
+			value = input.Default
+		}
+		if value == "" && input.Required && !hasStored {
+			continue
+		}
+		return value, nil
+	}
+}
+
+func installWithSecretReferences(def mcp.Definition, secretValues map[string]string, opts registryInstallOptions) ([]mcp.Installed, error) {
+	references := mcp.SecretReferences(def)
+	if len(references) == 0 {
+		return mcp.Install(def, opts.Scope, opts.Cwd, opts.Clients, opts.Force)
+	}
+	var records []mcp.Installed
+	for _, client := range opts.Clients {
+		clientDef := def
+		if unsupported := mcp.UnsupportedReferences(client, def); len(unsupported) > 0 {
+			clientDef, _ = mcp.ResolveSecretReferences(def, func(name string) (string, bool) {
+				if !slices.Contains(unsupported, name) {
+					return "", false
+				}
+				value, ok := secretValues[name]
+				return value, ok
+			})
+			if opts.ErrOut != nil {
+				fmt.Fprintf(opts.ErrOut, "warning: %s cannot read %s from the environment here; writing the value into its config\n", client, strings.Join(unsupported, ", "))
+			}
+		}
+		installed, err := mcp.Install(clientDef, opts.Scope, opts.Cwd, []installer.Tool{client}, opts.Force)
 		if err != nil {
 			return nil, err
 		}
-		values[name] = value
+		records = append(records, installed...)
+	}
+	if opts.ErrOut != nil {
+		fmt.Fprintf(opts.ErrOut, "note: %s must be set in the environment the clients start from (eval \"$(mcp secrets env)\")\n", strings.Join(references, ", "))
+		if err := opts.ErrOut.Flush(); err != nil {
+			return nil, err
+		}
 	}
-	return values, nil
+	return records, nil
 }
 
 func promptInput(reader *bufio.Reader, out *bufio.Writer, label string, input registryindex.MCPInput) (string, error) {
@@ -188,36 +474,16 @@ func promptInput(reader *bufio.Reader, out *bufio.Writer, label string, input re
 		if value == "" && input.Required {
 			continue
 		}
-		switch strings.ToLower(strings.TrimSpace(input.Type)) {
-		case "choice":
-			if len(input.Options) == 0 {
-				return value, nil
-			}
-			if idx, err := strconv.Atoi(value); err == nil && idx >= 1 && idx <= len(input.Options) {
-				return input.Options[idx-1], nil
-			}
-			if isOptionMatch(input.Options, value) {
-				return value, nil
-			}
-			fmt.Fprintln(out, "Invalid choice. Try again.")
-			_ = out.Flush()
-			continue
-		case "bool":
-			if value == "" && !input.Required {
-				return "", nil
-			}
-			switch strings.ToLower(value) {
-			case "y", "yes", "true", "1":
-				return "true", nil
-			case "n", "no", "false", "0":
-				return "false", nil
-			}
+		normalized, err := normalizeInputValue(input, value)
+		if err == nil {
+			return normalized, nil
+		}
+		if strings.EqualFold(strings.TrimSpace(input.Type), "bool") {
 			fmt.Fprintln(out, "Invalid choice. Use y/n.")
-			_ = out.Flush()
-			continue
-		default:
-			return value, nil
+		} else {
+			fmt.Fprintln(out, "Invalid choice. Try again.")
 		}
+		_ = out.Flush()
 	}
 }
 
@@ -231,16 +497,23 @@ func isOptionMatch(options []string, value string) bool {
 }
 
 func ensureRepo(entry registryindex.MCPEntry, opts registryInstallOptions) (string, bool, error) {
-	root, err := installer.LocalMcpStore()
+	dest, err := registryindex.MCPRepoInStore(entry.Registry, entry.Name)
 	if err != nil {
 		return "", false, err
 	}
-	if err := os.MkdirAll(root, 0o755); err != nil {
-		return "", false, err
+	if !plan.Active() {
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return "", false, err
+		}
 	}
-	dest := filepath.Join(root, entry.Name)
 	_, statErr := os.Stat(dest)
 	exists := statErr == nil
+	if registryindex.Offline() {
+		if !exists {
+			return "", false, registryindex.OfflineError{What: "server " + entry.Name + " (repository not in the local store)"}
+		}
+		return dest, false, nil
+	}
 	needsUpdate := true
 	if exists {
 		needs, err := needsMcpUpdate(entry)
@@ -251,7 +524,10 @@ func ensureRepo(entry registryindex.MCPEntry, opts registryInstallOptions) (stri
 		if !needsUpdate && !opts.Force {
 			return dest, false, nil
 		}
-		if !opts.Force {
+		if !opts.Force && opts.NonInteractive {
+			return dest, false, nil
+		}
+		if !opts.Force && !plan.Active() {
 			if !confirmUpdate(opts.Out, entry.Name) {
 				return dest, false, nil
 			}
@@ -260,7 +536,7 @@ func ensureRepo(entry registryindex.MCPEntry, opts registryInstallOptions) (stri
 
 	err = cli.RunWithSpinner(opts.SpinnerOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		if exists {
-			if err := os.RemoveAll(dest); err != nil {
+			if err := safefile.RemoveAll(dest); err != nil {
 				return err
 			}
 		}
@@ -268,11 +544,18 @@ func ensureRepo(entry registryindex.MCPEntry, opts registryInstallOptions) (stri
 		if err != nil {
 			return err
 		}
+		if plan.Active() {
+			plan.RecordCommand("", "git clone --depth 1 "+credentials.Redact(repo)+" "+dest)
+			if strings.TrimSpace(entry.Head) != "" {
+				plan.RecordCommand(dest, "git fetch --depth 1 origin "+entry.Head+" && git checkout "+entry.Head)
+			}
+			return nil
+		}
 		if err := gitClone(repo, dest); err != nil {
 			return err
 		}
 		if strings.TrimSpace(entry.Head) != "" {
-			if err := gitCheckout(dest, entry.Head); err != nil {
+			if err := gitCheckout(repo, dest, entry.Head); err != nil {
 				return err
 			}
 		}
@@ -289,7 +572,7 @@ func normalizeRepoURL(repo string) (string, error) {
 	if repo == "" {
 		return "", fmt.Errorf("repository is required")
 	}
-	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "git@") {
+	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "git@") || strings.HasPrefix(repo, "ssh://") {
 		return repo, nil
 	}
 	if strings.Count(repo, "/") == 1 {
@@ -299,7 +582,12 @@ func normalizeRepoURL(repo string) (string, error) {
 }
 
 func gitClone(repoURL, dest string) error {
+	token, err := credentials.Lookup(repoURL)
+	if err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "clone", "--depth", "1", repoURL, dest)
+	cmd.Env = credentials.GitEnv(repoURL, token)
 	var output bytes.Buffer
 	cmd.Stdout = &output
 	cmd.Stderr = &output
@@ -308,13 +596,18 @@ func gitClone(repoURL, dest string) error {
 		if msg == "" {
 			msg = err.Error()
 		}
-		return fmt.Errorf("git clone failed: %s", msg)
+		return fmt.Errorf("git clone failed: %s", credentials.Redact(msg, token))
 	}
 	return nil
 }
 
-func gitCheckout(repoPath, head string) error {
+func gitCheckout(repoURL, repoPath, head string) error {
+	token, err := credentials.Lookup(repoURL)
+	if err != nil {
+		return err
+	}
 	cmd := exec.Command("git", "fetch", "--depth", "1", "origin", head)
+	cmd.Env = credentials.GitEnv(repoURL, token)
 	cmd.Dir = repoPath
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git fetch failed: %v", err)
@@ -328,7 +621,7 @@ func gitCheckout(repoPath, head string) error {
 }
 
 func needsMcpUpdate(entry registryindex.MCPEntry) (bool, error) {
-	record, ok, err := registryindex.LocalRecordFor("mcp", entry.Name)
+	record, ok, err := registryindex.LoadRegistryRecord("mcp", entry.Registry, entry.Name)
 	if err != nil {
 		return true, err
 	}
@@ -378,6 +671,10 @@ func runInstallSteps(steps []string, repoPath string) error {
 		if step == "" {
 			continue
 		}
+		if plan.Active() {
+			plan.RecordCommand(repoPath, step)
+			continue
+		}
 		if err := runShellCommand(step, repoPath); err != nil {
 			return err
 		}
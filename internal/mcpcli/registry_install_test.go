@@ -0,0 +1,59 @@
+package mcpcli
+
+import (
+	"bufio"
+	"io"
+	"testing"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/secrets"
+)
+
+func TestCollectInputsMigratesPlaintextSecret(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(secrets.BackendEnv, secrets.BackendFile)
+	t.Setenv(secrets.PassphraseEnv, "correct horse")
+	entry := registryindex.MCPEntry{
+		Name:   "iserver",
+		Inputs: []registryindex.MCPInput{{Name: "TOKEN", Required: true, Secret: true, Env: "ISERVER_TOKEN"}},
+	}
+	err := mcp.UpdateSavedInputs(entry.Name, installer.ScopeUser, "", func(saved map[string]mcp.SavedInput) error {
+		saved["TOKEN"] = mcp.SavedInput{Value: "s3cret"}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := registryInstallOptions{Scope: installer.ScopeUser, NonInteractive: true, Out: bufio.NewWriter(io.Discard)}
+	values, secretValues, err := collectInputs(entry, opts)
+	if err != nil {
+		t.Fatalf("collectInputs: %v", err)
+	}
+	if got, want := values["TOKEN"], mcp.SecretReference("ISERVER_TOKEN"); got != want {
+		t.Fatalf("TOKEN = %q, want %q", got, want)
+	}
+	if got := secretValues["ISERVER_TOKEN"]; got != "s3cret" {
+		t.Fatalf("secret value = %q, want the remembered value", got)
+	}
+	store, err := secrets.Open(secrets.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := store.Get("ISERVER_TOKEN"); err != nil || !ok || got != "s3cret" {
+		t.Fatalf("vault value = %q, %v, %v; want the remembered value", got, ok, err)
+	}
+
+	if err := saveInputAnswers(entry, values, opts); err != nil {
+		t.Fatal(err)
+	}
+	saved, _, err := mcp.LoadSavedInputs(entry.Name, installer.ScopeUser, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Inputs["TOKEN"]; got != (mcp.SavedInput{Secret: "ISERVER_TOKEN"}) {
+		t.Fatalf("saved TOKEN = %+v, want a vault reference", got)
+	}
+}
@@ -0,0 +1,48 @@
+package mcpcli
+
+import (
+	"flag"
+	"fmt"
+
+	"mcp-skill-manager/internal/mcp"
+)
+
+func (a *App) runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printRestoreHelp()
+		return 0
+	}
+	if len(positionals) != 1 {
+		fmt.Fprintln(a.errOut, "restore requires exactly one backup id")
+		return 2
+	}
+
+	backup, err := mcp.RestoreBackup(positionals[0])
+	if err != nil {
+		fmt.Fprintf(a.errOut, "restore failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.out, "restored %s -> %s (%s)\n", backup.ID, backup.Path, backup.Client)
+	return 0
+}
+
+func (a *App) printRestoreHelp() {
+	fmt.Fprintf(a.out, `Usage: %s restore <id>
+
+What it does:
+  - Writes the snapshot <id> (see "%s backups") back to its client config
+  - The current config is backed up first, so a restore can itself be undone
+
+Examples:
+  %s restore claude/20260116-093012.412
+`, a.binaryName, a.binaryName, a.binaryName)
+}
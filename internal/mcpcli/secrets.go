@@ -0,0 +1,343 @@
+package mcpcli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/secrets"
+)
+
+type secretRecord struct {
+	Name     string `json:"name"`
+	Backend  string `json:"backend"`
+	Location string `json:"location"`
+}
+
+func (a *App) runSecrets(args []string) int {
+	if len(args) > 0 && isHelp(args[0]) {
+		a.printSecretsHelp()
+		return 0
+	}
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("secrets", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	shellFlag := fs.String("shell", "sh", "shell syntax for secrets env: sh, fish or powershell")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printSecretsHelp()
+		return 0
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
+	switch action {
+	case "list", "ls":
+		if len(positionals) > 0 {
+			fmt.Fprintln(a.errOut, "secrets list takes no arguments")
+			return 2
+		}
+	case "set", "get", "rm", "remove":
+		if len(positionals) != 1 {
+			fmt.Fprintf(a.errOut, "secrets %s requires exactly one secret name\n", action)
+			return 2
+		}
+		if err := secrets.ValidateName(positionals[0]); err != nil {
+			fmt.Fprintf(a.errOut, "secrets %s failed: %v\n", action, err)
+			return 2
+		}
+	case "env":
+		switch *shellFlag {
+		case "sh", "fish", "powershell":
+		default:
+			fmt.Fprintf(a.errOut, "invalid --shell: %s (expected sh, fish or powershell)\n", *shellFlag)
+			return 2
+		}
+	default:
+		fmt.Fprintf(a.errOut, "unknown secrets command: %s\n", action)
+		return 2
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	prompts := bufio.NewWriter(a.errOut)
+	store, err := openSecretStore(reader, prompts)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "secrets %s failed: %v\n", action, err)
+		return 1
+	}
+
+	switch action {
+	case "set":
+		name := positionals[0]
+		value, err := readSecretValue(reader, prompts, name)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "secrets set failed: %v\n", err)
+			return 1
+		}
+		if err := store.Set(name, value); err != nil {
+			fmt.Fprintf(a.errOut, "secrets set failed: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.out, "stored %s in %s\n", name, store.Location())
+		return 0
+	case "get":
+		value, ok, err := store.Get(positionals[0])
+		if err != nil {
+			fmt.Fprintf(a.errOut, "secrets get failed: %v\n", err)
+			return 1
+		}
+		if !ok {
+			fmt.Fprintf(a.errOut, "secrets get failed: %s is not stored\n", positionals[0])
+			return 1
+		}
+		fmt.Fprintln(a.out, value)
+		return 0
+	case "rm", "remove":
+		removed, err := store.Delete(positionals[0])
+		if err != nil {
+			fmt.Fprintf(a.errOut, "secrets rm failed: %v\n", err)
+			return 1
+		}
+		if !removed {
+			fmt.Fprintf(a.errOut, "secrets rm failed: %s is not stored\n", positionals[0])
+			return 1
+		}
+		fmt.Fprintf(a.out, "removed %s\n", positionals[0])
+		return 0
+	}
+
+	names := positionals
+	if len(names) == 0 {
+		names, err = store.Names()
+		if err != nil {
+			fmt.Fprintf(a.errOut, "secrets %s failed: %v\n", action, err)
+			return 1
+		}
+	}
+
+	if action == "env" {
+		for _, name := range names {
+			value, ok, err := store.Get(name)
+			if err != nil {
+				fmt.Fprintf(a.errOut, "secrets env failed: %v\n", err)
+				return 1
+			}
+			if !ok {
+				fmt.Fprintf(a.errOut, "secrets env failed: %s is not stored\n", name)
+				return 1
+			}
+			fmt.Fprintln(a.out, exportLine(*shellFlag, name, value))
+		}
+		return 0
+	}
+
+	if format.Structured() {
+		records := make([]secretRecord, 0, len(names))
+		for _, name := range names {
+			records = append(records, secretRecord{Name: name, Backend: store.Backend(), Location: store.Location()})
+		}
+		return a.writeOutput(format, "mcp.secrets", records)
+	}
+	fmt.Fprintf(a.out, "backend: %s (%s)\n", store.Backend(), store.Location())
+	if len(names) == 0 {
+		fmt.Fprintln(a.out, "no secrets stored")
+		return 0
+	}
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tIN ENVIRONMENT")
+	for _, name := range names {
+		inEnv := "no"
+		if _, ok := os.LookupEnv(name); ok {
+			inEnv = "yes"
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", name, inEnv)
+	}
+	writer.Flush()
+	return 0
+}
+
+func (a *App) unlockDefinition(command string, def mcp.Definition) (mcp.Definition, int) {
+	names := mcp.SecretReferences(def)
+	if len(names) == 0 {
+		return def, 0
+	}
+	values, code := a.lookupSecrets(command, names)
+	if code != 0 {
+		return mcp.Definition{}, code
+	}
+	resolved, _ := mcp.ResolveSecretReferences(def, func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	})
+	return resolved, 0
+}
+
+func (a *App) lookupSecrets(command string, names []string) (map[string]string, int) {
+	reader := bufio.NewReader(os.Stdin)
+	prompts := bufio.NewWriter(a.errOut)
+	values := make(map[string]string, len(names))
+	var store secrets.Store
+	var missing []string
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			values[name] = value
+			continue
+		}
+		if store == nil {
+			var err error
+			if store, err = openSecretStore(reader, prompts); err != nil {
+				fmt.Fprintf(a.errOut, "%s failed: %v\n", command, err)
+				return nil, 1
+			}
+		}
+		value, ok, err := store.Get(name)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "%s failed: %v\n", command, err)
+			return nil, 1
+		}
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		values[name] = value
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(a.errOut, "%s failed: %s is not set in the environment or stored in the secrets vault (see \"%s secrets set -h\")\n", command, strings.Join(missing, ", "), a.binaryName)
+		return nil, 1
+	}
+	return values, 0
+}
+
+func openSecretStore(reader *bufio.Reader, out *bufio.Writer) (secrets.Store, error) {
+	return secrets.Open(secrets.Options{
+		Passphrase: func(create bool) (string, error) {
+			if !create {
+				fmt.Fprint(out, "Vault passphrase: ")
+				return readHiddenLine(reader, out)
+			}
+			fmt.Fprint(out, "New vault passphrase: ")
+			first, err := readHiddenLine(reader, out)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprint(out, "Repeat passphrase: ")
+			second, err := readHiddenLine(reader, out)
+			if err != nil {
+				return "", err
+			}
+			if first != second {
+				return "", fmt.Errorf("passphrases do not match")
+			}
+			return first, nil
+		},
+	})
+}
+
+func readSecretValue(reader *bufio.Reader, out *bufio.Writer, name string) (string, error) {
+	if isTerminal(os.Stdin) {
+		fmt.Fprintf(out, "Value for %s: ", name)
+	}
+	value, err := readHiddenLine(reader, out)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("empty value for %s", name)
+	}
+	return value, nil
+}
+
+func readHiddenLine(reader *bufio.Reader, out *bufio.Writer) (string, error) {
+	if err := out.Flush(); err != nil {
+		return "", err
+	}
+	if isTerminal(os.Stdin) && runtime.GOOS != "windows" {
+		if setTerminalEcho(false) == nil {
+			defer func() {
+				setTerminalEcho(true)
+				fmt.Fprintln(out)
+				out.Flush()
+			}()
+		}
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func setTerminalEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+func exportLine(shell, name, value string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -gx %s '%s'", name, strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value))
+	case "powershell":
+		return fmt.Sprintf("$env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+	default:
+		return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+	}
+}
+
+func (a *App) printSecretsHelp() {
+	fmt.Fprintf(a.out, `Usage: %s secrets [list|set|get|rm|env] [NAME] [--shell sh|fish|powershell] [--output|-o json|yaml]
+
+What it does:
+  - Stores values for registry inputs marked "secret" (API keys, tokens) outside client configs
+  - Client configs reference them as environment variables instead, e.g. ${GITHUB_TOKEN} (Claude, Gemini), ${env:GITHUB_TOKEN} (Cursor, VS Code, Windsurf, Roo, Kilo), {env:GITHUB_TOKEN} (OpenCode), env_vars/bearer_token_env_var (Codex), env_keys (Goose)
+  - Backend: the OS keyring (macOS keychain via security, Secret Service via secret-tool) when available, otherwise an encrypted file vault at ~/.mcp-skill/secrets/vault.json (AES-256-GCM, PBKDF2 passphrase)
+  - %s=keyring|file forces a backend; %s unlocks the file vault without a prompt
+  - list: shows stored names and whether they are exported in the current environment
+  - set NAME: reads the value from a hidden prompt or from stdin
+  - env [NAME...]: prints export lines so clients started from the shell can read the secrets
+  - test, tools and call resolve references from the environment first, then the vault
+
+Examples:
+  %s secrets
+  %s secrets set GITHUB_TOKEN
+  printf '%%s' "$TOKEN" | %s secrets set GITHUB_TOKEN
+  eval "$(%s secrets env)"
+  %s secrets env --shell fish | source
+  %s secrets rm GITHUB_TOKEN
+`, a.binaryName, secrets.BackendEnv, secrets.PassphraseEnv, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
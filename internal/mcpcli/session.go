@@ -0,0 +1,101 @@
+package mcpcli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/mcpclient"
+)
+
+const defaultServerTimeout = 30 * time.Second
+
+func (a *App) lookupDefinition(command, name, clientFlag, clientShort string, global, local bool) (mcp.Definition, string, int) {
+	clientValue, err := resolveListClientValue(clientFlag, clientShort, "")
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return mcp.Definition{}, "", 2
+	}
+	clients, err := installer.ParseTools(clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
+		return mcp.Definition{}, "", 2
+	}
+	clients, err = normalizeMcpClients(clients, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return mcp.Definition{}, "", 2
+	}
+
+	cwd, _ := os.Getwd()
+	def, source, err := resolveDefinition(name, clients, resolveLookupScopes(global, local), cwd)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s failed: %v\n", command, err)
+		return mcp.Definition{}, "", 1
+	}
+	return def, source, 0
+}
+
+func openSession(ctx context.Context, def mcp.Definition) (*mcpclient.Client, mcpclient.InitializeResult, error) {
+	client, err := mcpclient.Connect(ctx, def, mcpclient.Options{})
+	if err != nil {
+		return nil, mcpclient.InitializeResult{}, err
+	}
+	init, err := client.Initialize(ctx)
+	if err != nil {
+		client.Close()
+		return nil, mcpclient.InitializeResult{}, err
+	}
+	return client, init, nil
+}
+
+func resolveLookupScopes(global bool, local bool) []string {
+	if global && !local {
+		return []string{installer.ScopeUser}
+	}
+	if local && !global {
+		return []string{installer.ScopeProject}
+	}
+	return []string{installer.ScopeProject, installer.ScopeUser}
+}
+
+func resolveDefinition(name string, clients []installer.Tool, scopes []string, cwd string) (mcp.Definition, string, error) {
+	if fileExists(name) {
+		def, err := mcp.LoadDefinitionFromFile(name)
+		if err != nil {
+			return mcp.Definition{}, "", err
+		}
+		return def, "file " + name, nil
+	}
+
+	var lookupErr error
+	for _, scope := range scopes {
+		for _, client := range clients {
+			if !mcp.SupportsScope(client, scope) {
+				continue
+			}
+			def, ok, err := mcp.Read(client, name, scope, cwd)
+			if err != nil {
+				if lookupErr == nil {
+					lookupErr = fmt.Errorf("%s (%s): %w", client, scope, err)
+				}
+				continue
+			}
+			if ok {
+				return def, fmt.Sprintf("%s (%s)", client, scope), nil
+			}
+		}
+	}
+
+	def, err := mcp.LoadLocalDefinition(name)
+	if err == nil {
+		return def, "local store", nil
+	}
+	if lookupErr != nil {
+		return mcp.Definition{}, "", fmt.Errorf("server not found: %s (%v)", name, lookupErr)
+	}
+	return mcp.Definition{}, "", fmt.Errorf("server not found: %s (not installed and not in the local store)", name)
+}
@@ -0,0 +1,417 @@
+package mcpcli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/manifest"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/registryindex"
+)
+
+type syncTarget struct {
+	server  manifest.MCPServer
+	clients []installer.Tool
+	scope   string
+}
+
+type syncOptions struct {
+	force          bool
+	frozen         bool
+	nonInteractive bool
+	registryErr    error
+}
+
+type syncResult struct {
+	name    string
+	client  installer.Tool
+	scope   string
+	message string
+	err     error
+}
+
+func (a *App) runSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	fileFlag := fs.String("file", "", "manifest path (default: nearest "+manifest.DefaultFile+")")
+	pruneFlag := fs.Bool("prune", false, "remove servers not listed in the manifest")
+	forceShort := fs.Bool("f", false, "reinstall every server even if up to date")
+	forceLong := fs.Bool("force", false, "reinstall every server even if up to date")
+	frozenFlag := fs.Bool("frozen", false, "refuse registry servers whose head differs from the lockfile")
+	nonInteractive := fs.Bool("non-interactive", false, "never prompt; take inputs from MCP_INPUT_<NAME> and prune without confirmation")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printSyncHelp()
+		return 0
+	}
+	if len(positionals) > 0 {
+		fmt.Fprintln(a.errOut, "sync does not accept positional arguments")
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	path := strings.TrimSpace(*fileFlag)
+	if path == "" {
+		found, err := manifest.Find(cwd)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+			return 1
+		}
+		path = found
+	}
+	m, err := manifest.Load(path)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+		return 1
+	}
+	if len(m.MCP) == 0 {
+		fmt.Fprintln(a.out, "no servers in manifest")
+		return 0
+	}
+
+	root := m.Dir()
+	targets, err := resolveSyncTargets(m)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid manifest: %v\n", err)
+		return 2
+	}
+
+	installed, err := listSyncInstalled(targets, root)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+		return 1
+	}
+
+	registryErr := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+		return registryindex.EnsureIndexes()
+	})
+
+	if registryErr != nil {
+		if *frozenFlag {
+			fmt.Fprintf(a.errOut, "sync failed: %v\n", registryErr)
+			return 1
+		}
+		fmt.Fprintf(a.errOut, "warning: registry index unavailable, only local servers can be synced: %v\n", registryErr)
+	}
+
+	opts := syncOptions{
+		force:          *forceShort || *forceLong,
+		frozen:         *frozenFlag,
+		nonInteractive: *nonInteractive,
+		registryErr:    registryErr,
+	}
+	var results []syncResult
+	for _, target := range targets {
+		results = append(results, a.syncServer(m, target, installed, root, opts)...)
+	}
+
+	if *pruneFlag {
+		extras := collectSyncExtras(targets, installed)
+		if len(extras) > 0 && (*nonInteractive || confirmRemoval(a.out, extras)) {
+			for _, item := range extras {
+				_, err := mcp.Uninstall(item.Name, item.Scope, root, []installer.Tool{item.Client}, true)
+				if err != nil {
+					results = append(results, syncResult{name: item.Name, client: item.Client, scope: item.Scope, err: err})
+					continue
+				}
+				results = append(results, syncResult{name: item.Name, client: item.Client, scope: item.Scope, message: "removed"})
+			}
+		}
+	}
+
+	rows := make([]cli.BatchRow, 0, len(results))
+	for _, res := range results {
+		row := cli.BatchRow{Name: res.name, Client: string(res.client), Scope: res.scope, Result: res.message, Outcome: cli.OutcomeChanged}
+		switch {
+		case res.err != nil:
+			fmt.Fprintf(a.errOut, "sync failed for %s (%s/%s): %v\n", res.name, res.client, res.scope, res.err)
+			row.Result = "failed"
+			row.Outcome = cli.OutcomeFailed
+		case res.message == "up to date":
+			row.Outcome = cli.OutcomeUnchanged
+		}
+		rows = append(rows, row)
+	}
+	if err := cli.PrintBatchTable(a.out, rows); err != nil {
+		fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+		return 1
+	}
+	if code := cli.SummarizeBatch(rows).ExitCode(); code == cli.ExitFailure || code == cli.ExitPartialFailure {
+		return code
+	}
+	return 0
+}
+
+func (a *App) syncServer(m manifest.Manifest, target syncTarget, installed map[string]mcp.Installed, cwd string, opts syncOptions) []syncResult {
+	name := strings.TrimSpace(target.server.Name)
+	var missing, present []installer.Tool
+	for _, client := range target.clients {
+		if _, ok := installed[syncKey(client, target.scope, name)]; ok {
+			present = append(present, client)
+			continue
+		}
+		missing = append(missing, client)
+	}
+
+	failAll := func(err error) []syncResult {
+		var results []syncResult
+		for _, client := range target.clients {
+			results = append(results, syncResult{name: name, client: client, scope: target.scope, err: err})
+		}
+		return results
+	}
+
+	var (
+		def      mcp.Definition
+		entry    registryindex.MCPEntry
+		registry bool
+		drifted  bool
+	)
+	source := m.ResolvePath(target.server.SourceName())
+	switch {
+	case target.server.HasInlineDefinition():
+		inline, err := mcp.DefinitionFromArgs(name, target.server.TransportName(), target.server.URL, target.server.Command, target.server.Args)
+		if err != nil {
+			return failAll(err)
+		}
+		inline.Env = target.server.Env
+		inline.Headers = target.server.Headers
+		def = inline
+		drifted = definitionDrifted(def)
+	case fileExists(source):
+		loaded, err := mcp.LoadDefinitionFromFile(source)
+		if err != nil {
+			return failAll(err)
+		}
+		loaded.Name = name
+		def = loaded
+		drifted = definitionDrifted(def)
+	default:
+		if opts.registryErr == nil {
+			found, ok, err := registryindex.FindMCP(source)
+			if err != nil {
+				return failAll(err)
+			}
+			if ok {
+				entry = found
+				registry = true
+				needs, err := needsMcpUpdate(entry)
+				if err != nil {
+					return failAll(err)
+				}
+				drifted = needs
+			}
+		}
+		if !registry && opts.frozen {
+			return failAll(fmt.Errorf("server not found in registry: %s", source))
+		}
+		if !registry {
+			loaded, err := mcp.LoadLocalDefinition(source)
+			if err != nil && opts.registryErr != nil {
+				return failAll(fmt.Errorf("server not found in local store: %s (registry index unavailable: %v)", source, opts.registryErr))
+			}
+			if err != nil {
+				return failAll(fmt.Errorf("server not found in registry or local store: %s", source))
+			}
+			loaded.Name = name
+			def = loaded
+		}
+	}
+
+	clients := missing
+	if drifted || opts.force {
+		clients = target.clients
+	}
+
+	var results []syncResult
+	if len(clients) == 0 {
+		for _, client := range present {
+			results = append(results, syncResult{name: name, client: client, scope: target.scope, message: "up to date"})
+		}
+		return results
+	}
+
+	var err error
+	if registry {
+		_, err = installFromRegistryEntry(entry, registryInstallOptions{
+			Scope:          target.scope,
+			Cwd:            cwd,
+			Clients:        clients,
+			Force:          drifted || opts.force,
+			Frozen:         opts.frozen,
+			NonInteractive: opts.nonInteractive,
+			Out:            bufio.NewWriter(a.out),
+			ErrOut:         bufio.NewWriter(a.errOut),
+			SpinnerOut:     a.errOut,
+		})
+	} else {
+		err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+			if _, err := mcp.SaveLocalDefinition(def); err != nil {
+				return err
+			}
+			_, err := mcp.Install(def, target.scope, cwd, clients, true)
+			return err
+		})
+	}
+	if err != nil {
+		return failAll(err)
+	}
+
+	for _, client := range target.clients {
+		message := "up to date"
+		if containsClient(clients, client) {
+			message = "installed"
+			if containsClient(present, client) {
+				message = "updated"
+			}
+		}
+		results = append(results, syncResult{name: name, client: client, scope: target.scope, message: message})
+	}
+	return results
+}
+
+func resolveSyncTargets(m manifest.Manifest) ([]syncTarget, error) {
+	var targets []syncTarget
+	for _, server := range m.MCP {
+		tools, scope, err := m.Targets(server.Clients, server.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", server.Name, err)
+		}
+		clients, err := normalizeMcpClients(tools, strings.Join(server.Clients, ","))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", server.Name, err)
+		}
+		clients, err = filterMcpScopes(clients, []string{scope}, strings.Join(server.Clients, ","))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", server.Name, err)
+		}
+		targets = append(targets, syncTarget{server: server, clients: clients, scope: scope})
+	}
+	return targets, nil
+}
+
+func listSyncInstalled(targets []syncTarget, cwd string) (map[string]mcp.Installed, error) {
+	installed := map[string]mcp.Installed{}
+	for _, target := range targets {
+		items, err := mcp.List([]string{target.scope}, cwd, target.clients)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			installed[syncKey(item.Client, item.Scope, item.Name)] = item
+		}
+	}
+	return installed, nil
+}
+
+func collectSyncExtras(targets []syncTarget, installed map[string]mcp.Installed) []mcp.Installed {
+	managed := map[string]bool{}
+	declared := map[string]bool{}
+	for _, target := range targets {
+		for _, client := range target.clients {
+			managed[string(client)+":"+target.scope] = true
+			declared[syncKey(client, target.scope, strings.TrimSpace(target.server.Name))] = true
+		}
+	}
+
+	var extras []mcp.Installed
+	for key, item := range installed {
+		if declared[key] || !managed[string(item.Client)+":"+item.Scope] {
+			continue
+		}
+		extras = append(extras, item)
+	}
+	sort.Slice(extras, func(i, j int) bool {
+		if extras[i].Client != extras[j].Client {
+			return extras[i].Client < extras[j].Client
+		}
+		if extras[i].Scope != extras[j].Scope {
+			return extras[i].Scope < extras[j].Scope
+		}
+		return extras[i].Name < extras[j].Name
+	})
+	return extras
+}
+
+func syncKey(client installer.Tool, scope, name string) string {
+	return string(client) + ":" + scope + ":" + name
+}
+
+func definitionDrifted(def mcp.Definition) bool {
+	saved, err := mcp.LoadLocalDefinition(def.Name)
+	if err != nil {
+		return true
+	}
+	return !sameDefinition(saved, def)
+}
+
+func sameDefinition(a, b mcp.Definition) bool {
+	if a.Transport != b.Transport || a.URL != b.URL || a.Command != b.Command {
+		return false
+	}
+	if len(a.Args) != len(b.Args) {
+		return false
+	}
+	for i := range a.Args {
+		if a.Args[i] != b.Args[i] {
+			return false
+		}
+	}
+	return sameStringMap(a.Env, b.Env) && sameStringMap(a.Headers, b.Headers)
+}
+
+func sameStringMap(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *App) printSyncHelp() {
+	fmt.Fprintf(a.out, `Usage: %s sync [--file <path>] [--prune] [--force|-f] [--frozen] [--non-interactive]
+
+What it does:
+  - Reads the "mcp" section of %s (searched from the current directory upwards)
+  - Project-scope entries are installed, listed and pruned in the directory holding the manifest
+  - Installs servers missing from the listed clients/scopes
+  - Reinstalls servers whose registry head or definition changed
+  - With --prune: removes servers not listed for the clients/scopes the manifest manages
+  - With --frozen: refuses registry servers whose head differs from .mcp-skill.lock.json
+  - With --non-interactive: never prompts; registry inputs come from MCP_INPUT_<NAME> (or the
+    defaults), servers with missing required inputs fail and --prune removes without asking
+    (the file vault needs MCP_SKILL_VAULT_PASSPHRASE)
+  - Exits 3 when some entries failed and 1 when all of them failed
+
+Manifest example:
+  {
+    "clients": ["claude", "codex"],
+    "scope": "user",
+    "mcp": [
+      { "name": "context7" },
+      { "name": "docs", "transport": "http", "url": "https://example.com/mcp", "clients": ["claude"], "scope": "project" },
+      { "name": "local", "source": "./mcp/local.json" }
+    ]
+  }
+
+Examples:
+  %s sync
+  %s sync --file ./.mcp-skill.json --prune
+`, a.binaryName, manifest.DefaultFile, a.binaryName, a.binaryName)
+}
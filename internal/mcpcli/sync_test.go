@@ -0,0 +1,74 @@
+package mcpcli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func syncProject(t *testing.T, manifest string) (string, string) {
+	t.Helper()
+	t.Setenv("MCP_SKILL_OFFLINE", "1")
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".mcp-skill.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(nested); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return root, nested
+}
+
+func TestSyncFromNestedDirectory(t *testing.T) {
+	root, nested := syncProject(t, `{"clients": ["claude"], "scope": "project", "mcp": [{"name": "docs", "transport": "http", "url": "https://example.com/mcp"}]}`)
+	config := filepath.Join(root, ".mcp.json")
+	if err := os.WriteFile(config, []byte(`{"mcpServers": {"stale": {"type": "http", "url": "https://example.com/stale"}}}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, errOut := runApp(t, "sync", "--prune", "--non-interactive")
+	if code != 0 {
+		t.Fatalf("sync exit %d\nstdout:\n%s\nstderr:\n%s", code, out, errOut)
+	}
+	if !strings.Contains(errOut, "warning: registry index unavailable") {
+		t.Fatalf("stderr does not warn about the registry:\n%s", errOut)
+	}
+	got := readFile(t, config)
+	if !strings.Contains(got, `"docs"`) || strings.Contains(got, `"stale"`) {
+		t.Fatalf("project config not synced:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(nested, ".mcp.json")); !os.IsNotExist(err) {
+		t.Fatalf("sync wrote a config in the working directory (err %v)", err)
+	}
+}
+
+func TestSyncReportsRegistryErrorPerServer(t *testing.T) {
+	syncProject(t, `{"clients": ["claude"], "scope": "project", "mcp": [{"name": "context7"}]}`)
+
+	code, _, errOut := runApp(t, "sync", "--non-interactive")
+	if code == 0 {
+		t.Fatal("sync of an unresolvable server succeeded")
+	}
+	if !strings.Contains(errOut, "sync failed for context7") || !strings.Contains(errOut, "registry index unavailable:") {
+		t.Fatalf("failure does not mention the registry error:\n%s", errOut)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
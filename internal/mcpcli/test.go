@@ -0,0 +1,251 @@
+package mcpcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/mcpclient"
+)
+
+type probeStep struct {
+	Name      string `json:"name"`
+	LatencyMs int64  `json:"latencyMs"`
+	Count     *int   `json:"count,omitempty"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type probeResult struct {
+	Name            string                    `json:"name"`
+	Source          string                    `json:"source"`
+	Transport       string                    `json:"transport"`
+	Target          string                    `json:"target"`
+	OK              bool                      `json:"ok"`
+	ProtocolVersion string                    `json:"protocolVersion,omitempty"`
+	Server          *mcpclient.Implementation `json:"server,omitempty"`
+	Capabilities    []string                  `json:"capabilities,omitempty"`
+	Steps           []probeStep               `json:"steps"`
+	LatencyMs       int64                     `json:"latencyMs"`
+	Error           string                    `json:"error,omitempty"`
+}
+
+func (a *App) runTest(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	globalShort := fs.Bool("g", false, "only look at global/user scope")
+	globalLong := fs.Bool("global", false, "only look at global/user scope")
+	localShort := fs.Bool("l", false, "only look at local/project scope")
+	localLong := fs.Bool("local", false, "only look at local/project scope")
+	projectLong := fs.Bool("project", false, "only look at local/project scope")
+	clientFlag := fs.String("client", "", "comma-separated clients to look in")
+	clientShort := fs.String("c", "", "alias for --client")
+	timeoutFlag := fs.Duration("timeout", defaultServerTimeout, "overall timeout for the probe")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printTestHelp()
+		return 0
+	}
+	if len(positionals) != 1 {
+		fmt.Fprintln(a.errOut, "test requires exactly one server name or definition file")
+		return 2
+	}
+	if *timeoutFlag <= 0 {
+		fmt.Fprintln(a.errOut, "--timeout must be positive")
+		return 2
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
+	def, source, code := a.lookupDefinition("test", positionals[0], *clientFlag, *clientShort, *globalShort || *globalLong, *localShort || *localLong || *projectLong)
+	if code != 0 {
+		return code
+	}
+	resolved, code := a.unlockDefinition("test", def)
+	if code != 0 {
+		return code
+	}
+
+	var result probeResult
+	_ = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+		result = probeServer(resolved, source, *timeoutFlag)
+		result.Target = definitionTarget(def)
+		return nil
+	})
+
+	if !result.OK {
+		code = 1
+	}
+	if format.Structured() {
+		if writeCode := a.writeOutput(format, "mcp.test", []probeResult{result}); writeCode != 0 {
+			return writeCode
+		}
+		return code
+	}
+	a.printProbeResult(result)
+	return code
+}
+
+func probeServer(def mcp.Definition, source string, timeout time.Duration) probeResult {
+	result := probeResult{
+		Name:      def.Name,
+		Source:    source,
+		Transport: def.Transport,
+		Target:    definitionTarget(def),
+		Steps:     []probeStep{},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	started := time.Now()
+
+	stepStart := time.Now()
+	client, init, err := openSession(ctx, def)
+	if err == nil {
+		defer client.Close()
+	}
+	step := probeStep{Name: "initialize", LatencyMs: time.Since(stepStart).Milliseconds()}
+	if err != nil {
+		step.Error = probeError(err, timeout)
+		result.Steps = append(result.Steps, step)
+		result.Error = step.Error
+		result.LatencyMs = time.Since(started).Milliseconds()
+		return result
+	}
+	result.Steps = append(result.Steps, step)
+	result.ProtocolVersion = init.ProtocolVersion
+	result.Server = &init.ServerInfo
+	result.Capabilities = init.Capabilities.Names()
+
+	lists := []struct {
+		name      string
+		supported bool
+		run       func() (int, error)
+	}{
+		{"tools/list", init.Capabilities.Tools != nil, func() (int, error) {
+			items, err := client.ListTools(ctx)
+			return len(items), err
+		}},
+		{"resources/list", init.Capabilities.Resources != nil, func() (int, error) {
+			items, err := client.ListResources(ctx)
+			return len(items), err
+		}},
+		{"prompts/list", init.Capabilities.Prompts != nil, func() (int, error) {
+			items, err := client.ListPrompts(ctx)
+			return len(items), err
+		}},
+	}
+	result.OK = true
+	for _, list := range lists {
+		step := probeStep{Name: list.name}
+		if !list.supported {
+			step.Skipped = true
+			result.Steps = append(result.Steps, step)
+			continue
+		}
+		stepStart := time.Now()
+		count, err := list.run()
+		step.LatencyMs = time.Since(stepStart).Milliseconds()
+		if err != nil {
+			step.Error = probeError(err, timeout)
+			result.OK = false
+			if result.Error == "" {
+				result.Error = step.Error
+			}
+		} else {
+			step.Count = &count
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	result.LatencyMs = time.Since(started).Milliseconds()
+	return result
+}
+
+func probeError(err error, timeout time.Duration) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("no answer within %s", timeout)
+	}
+	return err.Error()
+}
+
+func definitionTarget(def mcp.Definition) string {
+	if def.Transport == "http" {
+		return def.URL
+	}
+	return strings.TrimSpace(def.Command + " " + strings.Join(def.Args, " "))
+}
+
+func (a *App) printProbeResult(result probeResult) {
+	fmt.Fprintf(a.out, "%s from %s\n", result.Name, result.Source)
+	fmt.Fprintf(a.out, "%s: %s\n", result.Transport, result.Target)
+	if result.Server != nil {
+		server := result.Server.Name
+		if result.Server.Version != "" {
+			server += " " + result.Server.Version
+		}
+		fmt.Fprintf(a.out, "server: %s\n", server)
+		fmt.Fprintf(a.out, "protocol: %s\n", result.ProtocolVersion)
+		capabilities := "none"
+		if len(result.Capabilities) > 0 {
+			capabilities = strings.Join(result.Capabilities, ", ")
+		}
+		fmt.Fprintf(a.out, "capabilities: %s\n", capabilities)
+	}
+	fmt.Fprintln(a.out)
+
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "STEP\tLATENCY\tRESULT")
+	for _, step := range result.Steps {
+		switch {
+		case step.Skipped:
+			fmt.Fprintf(writer, "%s\t-\tnot supported\n", step.Name)
+		case step.Error != "":
+			fmt.Fprintf(writer, "%s\t%dms\tfailed\n", step.Name, step.LatencyMs)
+		case step.Count != nil:
+			fmt.Fprintf(writer, "%s\t%dms\t%d\n", step.Name, step.LatencyMs, *step.Count)
+		default:
+			fmt.Fprintf(writer, "%s\t%dms\tok\n", step.Name, step.LatencyMs)
+		}
+	}
+	writer.Flush()
+
+	if result.OK {
+		fmt.Fprintf(a.out, "\nok (%dms)\n", result.LatencyMs)
+		return
+	}
+	fmt.Fprintf(a.out, "\nfailed: %s\n", result.Error)
+}
+
+func (a *App) printTestHelp() {
+	fmt.Fprintf(a.out, `Usage: %s test <name|file> [--client|-c <list>] [--global|-g|--local|-l] [--timeout 30s] [--output|-o json|yaml]
+
+What it does:
+  - Starts a stdio server (with its env) or connects to an HTTP server (with its headers)
+  - Runs the MCP initialize handshake, then tools/list, resources/list and prompts/list
+  - Reports protocol version, server info, capabilities, item counts and latency per step
+  - Looks the server up in client configs (project scope first), then in the local store (~/.mcp-skill/mcp/<name>.json); a definition file path works too
+  - Exits 1 when the server fails to start, answer or list
+
+Examples:
+  %s test github
+  %s test filesystem -c claude -g
+  %s test ./my-server.json --timeout 10s
+  %s test github -o json
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
@@ -0,0 +1,142 @@
+package mcpcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-skill-manager/internal/mcptest"
+)
+
+func writeDefinition(t *testing.T, name string, def map[string]any) string {
+	t.Helper()
+	data, err := json.Marshal(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func stdioDefinition(t *testing.T, mode string) string {
+	return writeDefinition(t, "fake", map[string]any{
+		"transport": "stdio",
+		"command":   os.Args[0],
+		"args":      []string{"-test.run=^$"},
+		"env":       map[string]string{serverModeEnv: mode},
+	})
+}
+
+func runApp(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	var out, errOut bytes.Buffer
+	code := New("mcp", &out, &errOut).Run(args)
+	return code, out.String(), errOut.String()
+}
+
+func TestTestCommandProbesServers(t *testing.T) {
+	httpServer := httptest.NewServer((&mcptest.Server{}).Handler())
+	defer httpServer.Close()
+	legacyServer := httptest.NewServer((&mcptest.Server{}).LegacyHandler())
+	defer legacyServer.Close()
+
+	tests := []struct {
+		name      string
+		file      func(t *testing.T) string
+		transport string
+	}{
+		{"stdio", func(t *testing.T) string { return stdioDefinition(t, "serve") }, "stdio"},
+		{"streamable http", func(t *testing.T) string {
+			return writeDefinition(t, "fake", map[string]any{"transport": "http", "url": httpServer.URL + "/mcp"})
+		}, "http"},
+		{"legacy sse", func(t *testing.T) string {
+			return writeDefinition(t, "fake", map[string]any{"transport": "http", "url": legacyServer.URL + "/sse"})
+		}, "http"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, errOut := runApp(t, "test", tt.file(t), "-o", "json")
+			if code != 0 {
+				t.Fatalf("exit code %d\nstdout:\n%s\nstderr:\n%s", code, out, errOut)
+			}
+			var doc struct {
+				Kind  string        `json:"kind"`
+				Items []probeResult `json:"items"`
+			}
+			if err := json.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatalf("invalid json output: %v\n%s", err, out)
+			}
+			if doc.Kind != "mcp.test" || len(doc.Items) != 1 {
+				t.Fatalf("unexpected document: %s", out)
+			}
+			result := doc.Items[0]
+			if !result.OK || result.Transport != tt.transport || result.ProtocolVersion != mcptest.ProtocolVersion {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			if result.Server == nil || result.Server.Name != "fake" || result.Server.Version != "1.0.0" {
+				t.Fatalf("server info = %+v", result.Server)
+			}
+			if got := strings.Join(result.Capabilities, ","); got != "tools,resources,prompts" {
+				t.Fatalf("capabilities = %s", got)
+			}
+			wantCounts := map[string]int{"tools/list": 3, "resources/list": 1, "prompts/list": 1}
+			if len(result.Steps) != 4 || result.Steps[0].Name != "initialize" || result.Steps[0].Error != "" {
+				t.Fatalf("steps = %+v", result.Steps)
+			}
+			for _, step := range result.Steps[1:] {
+				if step.Error != "" || step.Count == nil || *step.Count != wantCounts[step.Name] {
+					t.Fatalf("step %s = %+v, want count %d", step.Name, step, wantCounts[step.Name])
+				}
+				if step.LatencyMs < 0 || step.LatencyMs > result.LatencyMs {
+					t.Fatalf("step %s latency %dms outside the total %dms", step.Name, step.LatencyMs, result.LatencyMs)
+				}
+			}
+		})
+	}
+}
+
+func TestTestCommandTextReport(t *testing.T) {
+	code, out, errOut := runApp(t, "test", stdioDefinition(t, "serve"))
+	if code != 0 {
+		t.Fatalf("exit code %d\n%s%s", code, out, errOut)
+	}
+	for _, pattern := range []string{
+		`(?m)^server: fake 1\.0\.0$`,
+		`(?m)^protocol: ` + regexp.QuoteMeta(mcptest.ProtocolVersion) + `$`,
+		`(?m)^capabilities: tools, resources, prompts$`,
+		`(?m)^STEP\s+LATENCY\s+RESULT$`,
+		`(?m)^initialize\s+\d+ms\s+ok$`,
+		`(?m)^tools/list\s+\d+ms\s+3$`,
+		`(?m)^resources/list\s+\d+ms\s+1$`,
+		`(?m)^prompts/list\s+\d+ms\s+1$`,
+		`(?m)^ok \(\d+ms\)$`,
+	} {
+		if !regexp.MustCompile(pattern).MatchString(out) {
+			t.Errorf("output does not match %s:\n%s", pattern, out)
+		}
+	}
+}
+
+func TestTestCommandTimesOut(t *testing.T) {
+	started := time.Now()
+	code, out, errOut := runApp(t, "test", stdioDefinition(t, "silent"), "--timeout", "300ms")
+	if code != 1 {
+		t.Fatalf("exit code %d, want 1\n%s%s", code, out, errOut)
+	}
+	if !strings.Contains(out, "failed: no answer within 300ms") || !regexp.MustCompile(`(?m)^initialize\s+\d+ms\s+failed$`).MatchString(out) {
+		t.Fatalf("output does not report the timeout:\n%s", out)
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Fatalf("mcp test took %s with --timeout 300ms", elapsed)
+	}
+}
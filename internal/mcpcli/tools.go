@@ -0,0 +1,161 @@
+package mcpcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/mcpclient"
+)
+
+func (a *App) runTools(args []string) int {
+	fs := flag.NewFlagSet("tools", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	globalShort := fs.Bool("g", false, "only look at global/user scope")
+	globalLong := fs.Bool("global", false, "only look at global/user scope")
+	localShort := fs.Bool("l", false, "only look at local/project scope")
+	localLong := fs.Bool("local", false, "only look at local/project scope")
+	projectLong := fs.Bool("project", false, "only look at local/project scope")
+	clientFlag := fs.String("client", "", "comma-separated clients to look in")
+	clientShort := fs.String("c", "", "alias for --client")
+	timeoutFlag := fs.Duration("timeout", defaultServerTimeout, "timeout for starting the server and listing tools")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printToolsHelp()
+		return 0
+	}
+	if len(positionals) == 0 || len(positionals) > 2 {
+		fmt.Fprintln(a.errOut, "tools requires a server name and accepts an optional tool name")
+		return 2
+	}
+	if *timeoutFlag <= 0 {
+		fmt.Fprintln(a.errOut, "--timeout must be positive")
+		return 2
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
+	def, _, code := a.lookupDefinition("tools", positionals[0], *clientFlag, *clientShort, *globalShort || *globalLong, *localShort || *localLong || *projectLong)
+	if code != 0 {
+		return code
+	}
+	def, code = a.unlockDefinition("tools", def)
+	if code != 0 {
+		return code
+	}
+
+	var tools []mcpclient.Tool
+	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		client, _, err := openSession(ctx, def)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		tools, err = client.ListTools(ctx)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(a.errOut, "tools failed: %v\n", err)
+		return 1
+	}
+
+	if len(positionals) == 2 {
+		tool, ok := findTool(tools, positionals[1])
+		if !ok {
+			fmt.Fprintf(a.errOut, "tools failed: %s has no tool named %s (available: %s)\n", def.Name, positionals[1], toolNames(tools))
+			return 1
+		}
+		tools = []mcpclient.Tool{tool}
+	}
+
+	if format.Structured() {
+		if tools == nil {
+			tools = []mcpclient.Tool{}
+		}
+		return a.writeOutput(format, "mcp.tools", tools)
+	}
+	if len(tools) == 0 {
+		fmt.Fprintf(a.out, "%s has no tools\n", def.Name)
+		return 0
+	}
+	for idx, tool := range tools {
+		if idx > 0 {
+			fmt.Fprintln(a.out)
+		}
+		fmt.Fprintln(a.out, tool.Name)
+		if tool.Title != "" && tool.Title != tool.Name {
+			fmt.Fprintf(a.out, "  title: %s\n", tool.Title)
+		}
+		if description := strings.TrimSpace(tool.Description); description != "" {
+			for _, line := range strings.Split(description, "\n") {
+				fmt.Fprintf(a.out, "  %s\n", strings.TrimRight(line, " \t"))
+			}
+		}
+		if len(tool.InputSchema) > 0 {
+			fmt.Fprintln(a.out, "  input schema:")
+			fmt.Fprint(a.out, indentJSON(tool.InputSchema, "    "))
+		}
+	}
+	return 0
+}
+
+func findTool(tools []mcpclient.Tool, name string) (mcpclient.Tool, bool) {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return mcpclient.Tool{}, false
+}
+
+func toolNames(tools []mcpclient.Tool) string {
+	if len(tools) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func indentJSON(raw json.RawMessage, prefix string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, prefix, "  "); err != nil {
+		return prefix + string(raw) + "\n"
+	}
+	return prefix + buf.String() + "\n"
+}
+
+func (a *App) printToolsHelp() {
+	fmt.Fprintf(a.out, `Usage: %s tools <name|file> [tool] [--client|-c <list>] [--global|-g|--local|-l] [--timeout 30s] [--output|-o json|yaml]
+
+What it does:
+  - Starts or connects to the server and lists its tools via MCP tools/list
+  - Prints each tool's name, description and input JSON schema
+  - Pass a tool name to show only that tool
+  - The server is looked up like "%s test": client configs first, then the local store or a definition file
+
+Examples:
+  %s tools github
+  %s tools github create_issue
+  %s tools filesystem -c claude -g -o json
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
@@ -9,6 +9,7 @@ import (
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
 	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
 )
 
 func (a *App) runUninstall(args []string) int {
@@ -24,9 +25,12 @@ func (a *App) runUninstall(args []string) int {
 	forceLong := fs.Bool("force", false, "ignore missing servers")
 	allShort := fs.Bool("a", false, "remove for all clients")
 	allLong := fs.Bool("all", false, "remove for all clients")
-	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode")
+	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,windsurf,roo,kilocode,copilot,goose")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing anything")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -39,6 +43,19 @@ func (a *App) runUninstall(args []string) int {
 		a.printUninstallHelp()
 		return 0
 	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
+	promptOut := a.out
+	if format.Structured() {
+		promptOut = a.errOut
+	}
 
 	allRequested := *allShort || *allLong
 	clientValue, err := resolveClientValue(*clientFlag, *clientShort, *toolFlag, allRequested)
@@ -52,11 +69,22 @@ func (a *App) runUninstall(args []string) int {
 		return 2
 	}
 
+	clients, err = normalizeMcpClients(clients, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
+
 	normalizedScope, err := resolveScope(*scope, *globalShort || *globalLong, *localShort || *localLong || *projectLong)
 	if err != nil {
 		fmt.Fprintf(a.errOut, "invalid scope: %v\n", err)
 		return 2
 	}
+	clients, err = filterMcpScopes(clients, []string{normalizedScope}, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
 
 	if allRequested {
 		targets, err := collectRemovalTargets(positionals, normalizedScope, clients)
@@ -65,11 +93,17 @@ func (a *App) runUninstall(args []string) int {
 			return 1
 		}
 		if len(targets) == 0 {
+			if format.Structured() {
+				return a.writeOutput(format, "mcp.uninstall", nil)
+			}
 			fmt.Fprintln(a.out, "no matching servers found")
 			return 0
 		}
-		if !confirmRemoval(a.out, targets) {
-			fmt.Fprintln(a.out, "canceled")
+		if !*dryRun && !confirmRemoval(promptOut, targets) {
+			fmt.Fprintln(promptOut, "canceled")
+			if format.Structured() {
+				return a.writeOutput(format, "mcp.uninstall", nil)
+			}
 			return 0
 		}
 	}
@@ -80,6 +114,10 @@ func (a *App) runUninstall(args []string) int {
 		fmt.Fprintln(a.errOut, "uninstall requires a server name (or use -a)")
 		return 2
 	}
+	if *dryRun {
+		plan.Start()
+		defer plan.Stop()
+	}
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		var uninstallErr error
 		if len(positionals) == 0 {
@@ -94,6 +132,13 @@ func (a *App) runUninstall(args []string) int {
 		fmt.Fprintf(a.errOut, "uninstall failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+		return 0
+	}
+	if format.Structured() {
+		return a.writeOutput(format, "mcp.uninstall", installedResults(records, "removed"))
+	}
 
 	for _, record := range records {
 		fmt.Fprintf(a.out, "removed %s from %s (%s)\n", record.Name, record.Path, record.Client)
@@ -102,11 +147,12 @@ func (a *App) runUninstall(args []string) int {
 }
 
 func (a *App) printUninstallHelp() {
-	fmt.Fprintf(a.out, `Usage: %s uninstall [name] [--global|-g] [--local|-l] [--force|-f] [--client|-c <list>] [--all|-a]
+	fmt.Fprintf(a.out, `Usage: %s uninstall [name] [--global|-g] [--local|-l] [--force|-f] [--dry-run] [--output|-o <fmt>] [--client|-c <list>] [--all|-a]
 
 What it does:
   - Removes MCP servers from client config
   - Use --all to remove every installed server for the selected scope/clients
+  - With --dry-run: prints a diff of each config that would change without writing it
 
 Examples:
   %s uninstall github -l -c claude
@@ -9,6 +9,7 @@ import (
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
 	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/registryindex"
 )
 
@@ -20,9 +21,15 @@ func (a *App) runUpdate(args []string) int {
 	localShort := fs.Bool("l", false, "update local/project scope")
 	localLong := fs.Bool("local", false, "update local/project scope")
 	projectLong := fs.Bool("project", false, "update local/project scope")
-	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode")
+	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,windsurf,roo,kilocode,copilot,goose")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing anything")
+	nonInteractive := fs.Bool("non-interactive", false, "never prompt; take inputs from MCP_INPUT_<NAME> and fail when required ones are missing")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -34,6 +41,25 @@ func (a *App) runUpdate(args []string) int {
 		a.printUpdateHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
+	promptOut := a.out
+	if format.Structured() {
+		promptOut = a.errOut
+	}
 	if len(positionals) > 1 {
 		fmt.Fprintln(a.errOut, "update accepts at most one server name")
 		return 2
@@ -59,8 +85,9 @@ func (a *App) runUpdate(args []string) int {
 		return 2
 	}
 	scopes := resolveListScopes(*globalShort || *globalLong, *localShort || *localLong || *projectLong)
-	if containsScope(scopes, installer.ScopeProject) && containsClient(clients, installer.ToolCodex) {
-		fmt.Fprintln(a.errOut, "codex MCP only supports user scope; use --global")
+	clients, err = filterMcpScopes(clients, scopes, clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
 		return 2
 	}
 	cwd, _ := os.Getwd()
@@ -70,8 +97,12 @@ func (a *App) runUpdate(args []string) int {
 		return 1
 	}
 	if len(items) == 0 {
-		fmt.Fprintln(a.out, "no servers installed")
-		return 0
+		if format.Structured() {
+			a.writeOutput(format, "mcp.update", nil)
+		} else {
+			fmt.Fprintln(a.out, "no servers installed")
+		}
+		return cli.ExitNothingToDo
 	}
 
 	var targets []mcp.Installed
@@ -82,8 +113,12 @@ func (a *App) runUpdate(args []string) int {
 		targets = append(targets, item)
 	}
 	if len(targets) == 0 {
-		fmt.Fprintln(a.out, "no matching servers found")
-		return 0
+		if format.Structured() {
+			a.writeOutput(format, "mcp.update", nil)
+		} else {
+			fmt.Fprintln(a.out, "no matching servers found")
+		}
+		return cli.ExitNothingToDo
 	}
 
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
@@ -93,68 +128,139 @@ func (a *App) runUpdate(args []string) int {
 		fmt.Fprintf(a.errOut, "update failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		if err := startDryRun(true); err != nil {
+			fmt.Fprintf(a.errOut, "update failed: %v\n", err)
+			return 1
+		}
+		defer plan.Stop()
+	}
 
 	type result struct {
 		item    mcp.Installed
 		message string
+		outcome cli.Outcome
 		err     error
 	}
 	var results []result
+	fail := func(item mcp.Installed, err error) {
+		results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
+	}
 	for _, item := range targets {
-		entry, ok, err := registryindex.FindMCP(item.Name)
+		entry, ok, err := registryindex.FindMCP(registryindex.RecordedSource("mcp", item.Name))
 		if err != nil {
-			results = append(results, result{item: item, err: err})
+			fail(item, err)
 			continue
 		}
 		if !ok {
-			results = append(results, result{item: item, message: "not in registry"})
+			results = append(results, result{item: item, message: "not in registry", outcome: cli.OutcomeUnchanged})
 			continue
 		}
+		if registryindex.Offline() {
+			entry = registryindex.CachedMCPEntry(entry)
+		}
 
 		needsUpdate, err := needsMcpUpdate(entry)
 		if err != nil {
-			results = append(results, result{item: item, err: err})
+			fail(item, err)
 			continue
 		}
 		if !needsUpdate {
-			results = append(results, result{item: item, message: "already latest"})
+			results = append(results, result{item: item, message: "already latest", outcome: cli.OutcomeUnchanged})
 			continue
 		}
 
 		_, err = installFromRegistryEntry(entry, registryInstallOptions{
-			Scope:      item.Scope,
-			Cwd:        cwd,
-			Clients:    []installer.Tool{item.Client},
-			Force:      true,
-			Out:        bufio.NewWriter(a.out),
-			ErrOut:     bufio.NewWriter(a.errOut),
-			SpinnerOut: a.errOut,
+			Scope:          item.Scope,
+			Cwd:            cwd,
+			Clients:        []installer.Tool{item.Client},
+			Force:          true,
+			NonInteractive: *nonInteractive,
+			Out:            bufio.NewWriter(promptOut),
+			ErrOut:         bufio.NewWriter(a.errOut),
+			SpinnerOut:     a.errOut,
 		})
 		if err != nil {
-			results = append(results, result{item: item, err: err})
+			fail(item, err)
+			continue
+		}
+		if *dryRun {
+			results = append(results, result{item: item, message: "would update", outcome: cli.OutcomePending})
 			continue
 		}
-		results = append(results, result{item: item, message: "updated"})
+		results = append(results, result{item: item, message: "updated", outcome: cli.OutcomeChanged})
+	}
+
+	rows := make([]cli.BatchRow, 0, len(results))
+	for _, res := range results {
+		rows = append(rows, cli.BatchRow{
+			Name:    res.item.Name,
+			Client:  string(res.item.Client),
+			Scope:   res.item.Scope,
+			Result:  res.message,
+			Outcome: res.outcome,
+		})
+	}
+	code := cli.SummarizeBatch(rows).ExitCode()
+
+	if format.Structured() {
+		records := make([]resultRecord, 0, len(results))
+		for _, res := range results {
+			record := resultRecord{Installed: res.item, Status: res.message}
+			if res.err != nil {
+				record.Error = res.err.Error()
+			}
+			records = append(records, record)
+		}
+		if rc := a.writeOutput(format, "mcp.update", records); rc != 0 {
+			return rc
+		}
+		return code
 	}
 	for _, res := range results {
 		if res.err != nil {
 			fmt.Fprintf(a.errOut, "update failed for %s (%s/%s): %v\n", res.item.Name, res.item.Client, res.item.Scope, res.err)
-			continue
 		}
-		fmt.Fprintf(a.out, "%s (%s/%s): %s\n", res.item.Name, res.item.Client, res.item.Scope, res.message)
 	}
-	return 0
+	if err := cli.PrintBatchTable(a.out, rows); err != nil {
+		fmt.Fprintf(a.errOut, "update failed: %v\n", err)
+		return 1
+	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+	}
+	return code
 }
 
 func (a *App) printUpdateHelp() {
-	fmt.Fprintf(a.out, `Usage: %s update [name] [--global|-g] [--local|-l] [--client|-c <list>]
+	fmt.Fprintf(a.out, `Usage: %s update [name] [--global|-g] [--local|-l] [--dry-run] [--non-interactive] [--output|-o <fmt>] [--client|-c <list>] [--offline] [--refresh]
 
 What it does:
   - Checks registry for changes and reinstalls when needed
   - If name is omitted, updates all installed servers in the selected scope/clients
+  - With --dry-run: prints the planned config changes and commands without writing anything
+  - Reuses the inputs saved by install for each server and scope, prompting only for new ones
+    (see "%s config -h")
+  - With --non-interactive: never prompts; inputs come from MCP_INPUT_<NAME>, the saved answers
+    or the defaults, and servers with missing required inputs fail
+    (the file vault needs MCP_SKILL_VAULT_PASSPHRASE)
+  - With --offline (or MCP_SKILL_OFFLINE=1): brings servers up to date with the local cache
+    without the network or git; servers whose repository is not cached are reported as
+    "unavailable offline"
+  - Registry indexes are revalidated with conditional requests once MCP_REGISTRY_TTL (default
+    5m) has passed; --refresh revalidates them now
+  - Ends with a NAME/CLIENT/SCOPE/RESULT table and a summary line
+
+Exit codes:
+  0  at least one server updated, none failed
+  1  every server failed (or the command could not run)
+  2  invalid usage
+  3  partial failure: some servers failed, others succeeded or were already latest
+  4  nothing to do: no matching servers, or all already latest
+  5  update available (--dry-run found servers that would be updated)
 
 Examples:
   %s update
   %s update github -g -c claude
-`, a.binaryName, a.binaryName, a.binaryName)
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
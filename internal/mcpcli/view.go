@@ -20,9 +20,13 @@ func (a *App) runView(args []string) int {
 	localShort := fs.Bool("l", false, "show local/project scope (installed only)")
 	localLong := fs.Bool("local", false, "show local/project scope (installed only)")
 	projectLong := fs.Bool("project", false, "show local/project scope (installed only)")
-	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode (installed only)")
+	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,windsurf,roo,kilocode,copilot,goose (installed only)")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -34,6 +38,12 @@ func (a *App) runView(args []string) int {
 		a.printViewHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
 	if len(positionals) == 0 {
 		fmt.Fprintln(a.errOut, "view requires a server name")
 		return 2
@@ -43,6 +53,12 @@ func (a *App) runView(args []string) int {
 		return 2
 	}
 
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
 	name := positionals[0]
 	if *installedFlag {
 		clientValue, err := resolveListClientValue(*clientFlag, *clientShort, *toolFlag)
@@ -61,8 +77,9 @@ func (a *App) runView(args []string) int {
 			return 2
 		}
 		scopes := resolveListScopes(*globalShort || *globalLong, *localShort || *localLong || *projectLong)
-		if containsScope(scopes, installer.ScopeProject) && containsClient(clients, installer.ToolCodex) {
-			fmt.Fprintln(a.errOut, "codex MCP only supports user scope; use --global")
+		clients, err = filterMcpScopes(clients, scopes, clientValue)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
 			return 2
 		}
 		cwd, _ := os.Getwd()
@@ -83,11 +100,24 @@ func (a *App) runView(args []string) int {
 				matches = append(matches, item)
 			}
 		}
+		localDef, localErr := mcp.LoadLocalDefinition(name)
+		if format.Structured() {
+			records := []installedRecord{}
+			for _, item := range matches {
+				record := installedRecord{Installed: item}
+				if def, ok, err := mcp.Read(item.Client, name, item.Scope, cwd); err == nil && ok {
+					record.Definition = &def
+				} else if localErr == nil {
+					record.Definition = &localDef
+				}
+				records = append(records, record)
+			}
+			return a.writeOutput(format, "mcp.installed", records)
+		}
 		if len(matches) == 0 {
 			fmt.Fprintln(a.out, "no matching servers found")
 			return 0
 		}
-		def, defErr := mcp.LoadLocalDefinition(name)
 		for idx, item := range matches {
 			if idx > 0 {
 				fmt.Fprintln(a.out)
@@ -95,14 +125,16 @@ func (a *App) runView(args []string) int {
 			fmt.Fprintf(a.out, "%s (%s)\n", item.Client, item.Scope)
 			fmt.Fprintf(a.out, "path: %s\n", item.Path)
 			fmt.Fprintf(a.out, "transport: %s\n", displayTransport(item.Transport))
-			if defErr == nil {
+			if def, ok, err := mcp.Read(item.Client, name, item.Scope, cwd); err == nil && ok {
 				printDefinition(a.out, def)
+			} else if localErr == nil {
+				printDefinition(a.out, localDef)
 			}
 		}
 		return 0
 	}
 
-	err := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		return registryindex.EnsureIndexes()
 	})
 	if err != nil {
@@ -114,20 +146,32 @@ func (a *App) runView(args []string) int {
 		fmt.Fprintf(a.errOut, "view failed: %v\n", err)
 		return 1
 	}
+	if format.Structured() {
+		entries := []registryindex.MCPEntry{}
+		if ok {
+			entries = append(entries, entry)
+		}
+		return a.writeOutput(format, "mcp.registry", entries)
+	}
 	if !ok {
 		fmt.Fprintln(a.out, "server not found in registry")
 		return 0
 	}
 	printMcpEntry(a.out, entry)
+	if registryindex.Offline() && !registryindex.MCPAvailableOffline(entry) {
+		fmt.Fprintf(a.errOut, "offline: %s needs the network to install (its repository is not in ~/.mcp-skill/mcp)\n", entry.Name)
+	}
 	return 0
 }
 
 func (a *App) printViewHelp() {
-	fmt.Fprintf(a.out, `Usage: %s view <name> [--installed] [--global|-g] [--local|-l] [--client|-c <list>]
+	fmt.Fprintf(a.out, `Usage: %s view <name> [--installed] [--global|-g] [--local|-l] [--client|-c <list>] [--output|-o <text|json|yaml>] [--offline] [--refresh]
 
 What it does:
   - Default: show registry metadata for a server
   - With --installed: show installed server details and local definition
+  - With --offline (or MCP_SKILL_OFFLINE=1): reads the cached registry index only
+  - With --output json|yaml: print {schemaVersion, kind, items} records
 
 Examples:
   %s view context7
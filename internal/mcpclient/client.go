@@ -0,0 +1,338 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"mcp-skill-manager/internal/httpclient"
+	"mcp-skill-manager/internal/mcp"
+)
+
+const cancelNotifyTimeout = 2 * time.Second
+
+type Options struct {
+	ClientInfo     Implementation
+	Stderr         io.Writer
+	OnNotification func(Notification)
+}
+
+type transport interface {
+	start(ctx context.Context, deliver func(*message), fail func(error)) error
+	send(ctx context.Context, msg *message) error
+	close() error
+}
+
+type Client struct {
+	transport transport
+	info      Implementation
+	onNotify  func(Notification)
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *message
+	err     error
+	done    chan struct{}
+	server  InitializeResult
+}
+
+func Connect(ctx context.Context, def mcp.Definition, opts Options) (*Client, error) {
+	var t transport
+	switch def.Transport {
+	case "stdio":
+		t = newStdioTransport(def, opts.Stderr)
+	case "http":
+		httpTransport, err := newHTTPTransport(def)
+		if err != nil {
+			return nil, err
+		}
+		t = httpTransport
+	default:
+		return nil, fmt.Errorf("unsupported transport: %s", def.Transport)
+	}
+
+	info := opts.ClientInfo
+	if info.Name == "" {
+		info = defaultClientInfo()
+	}
+	c := &Client{
+		transport: t,
+		info:      info,
+		onNotify:  opts.OnNotification,
+		pending:   map[string]chan *message{},
+		done:      make(chan struct{}),
+	}
+	if err := t.start(ctx, c.dispatch, c.fail); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) Initialize(ctx context.Context) (InitializeResult, error) {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      c.info,
+	}
+	var result InitializeResult
+	if err := c.call(ctx, "initialize", params, &result); err != nil {
+		return InitializeResult{}, err
+	}
+	if result.ProtocolVersion == "" {
+		return InitializeResult{}, fmt.Errorf("initialize response has no protocolVersion")
+	}
+	if setter, ok := c.transport.(interface{ setProtocolVersion(string) }); ok {
+		setter.setProtocolVersion(result.ProtocolVersion)
+	}
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return InitializeResult{}, err
+	}
+	c.mu.Lock()
+	c.server = result
+	c.mu.Unlock()
+	return result, nil
+}
+
+func (c *Client) ServerInfo() InitializeResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.server
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]any) (CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	params := map[string]any{"name": name, "arguments": arguments}
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return CallToolResult{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) Notify(ctx context.Context, method string, params any) error {
+	return c.notify(ctx, method, params)
+}
+
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	err := c.paginate(ctx, "tools/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		tools = append(tools, page.Tools...)
+		return page.NextCursor, nil
+	})
+	return tools, err
+}
+
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	err := c.paginate(ctx, "resources/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		resources = append(resources, page.Resources...)
+		return page.NextCursor, nil
+	})
+	return resources, err
+}
+
+func (c *Client) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	var prompts []Prompt
+	err := c.paginate(ctx, "prompts/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Prompts    []Prompt `json:"prompts"`
+			NextCursor string   `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		prompts = append(prompts, page.Prompts...)
+		return page.NextCursor, nil
+	})
+	return prompts, err
+}
+
+func (c *Client) Close() error {
+	c.fail(fmt.Errorf("client closed"))
+	return c.transport.close()
+}
+
+func (c *Client) paginate(ctx context.Context, method string, page func(json.RawMessage) (string, error)) error {
+	cursor := ""
+	seen := map[string]bool{}
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var raw json.RawMessage
+		if err := c.call(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		next, err := page(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s response: %w", method, err)
+		}
+		if next == "" {
+			return nil
+		}
+		if seen[next] {
+			return fmt.Errorf("%s returned a repeated cursor", method)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.nextID++
+	id := json.RawMessage(strconv.FormatInt(c.nextID, 10))
+	reply := make(chan *message, 1)
+	c.pending[string(id)] = reply
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, string(id))
+		c.mu.Unlock()
+	}()
+
+	msg, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	msg.ID = id
+	if err := c.transport.send(ctx, msg); err != nil {
+		if ctx.Err() != nil {
+			c.cancelRequest(method, id, ctx.Err())
+		}
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	select {
+	case resp := <-reply:
+		if resp.Error != nil {
+			return fmt.Errorf("%s: %w", method, resp.Error)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("invalid %s response: %w", method, err)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("%s: %w", method, c.closedErr())
+	case <-ctx.Done():
+		c.cancelRequest(method, id, ctx.Err())
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+func (c *Client) cancelRequest(method string, id json.RawMessage, reason error) {
+	if method == "initialize" || c.closedErr() != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotifyTimeout)
+	defer cancel()
+	c.notify(ctx, "notifications/cancelled", map[string]any{
+		"requestId": id,
+		"reason":    reason.Error(),
+	})
+}
+
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	msg, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	if err := c.transport.send(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
+
+func (c *Client) dispatch(msg *message) {
+	switch {
+	case msg.isResponse():
+		c.mu.Lock()
+		reply, ok := c.pending[string(msg.ID)]
+		delete(c.pending, string(msg.ID))
+		c.mu.Unlock()
+		if ok {
+			select {
+			case reply <- msg:
+			default:
+			}
+		}
+	case msg.isRequest():
+		resp := &message{JSONRPC: "2.0", ID: msg.ID}
+		if msg.Method == "ping" {
+			resp.Result = json.RawMessage("{}")
+		} else {
+			resp.Error = &RPCError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+		}
+		go c.transport.send(context.Background(), resp)
+	case msg.Method != "":
+		if c.onNotify != nil {
+			c.onNotify(Notification{Method: msg.Method, Params: msg.Params})
+		}
+	}
+}
+
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+func (c *Client) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func newMessage(method string, params any) (*message, error) {
+	msg := &message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = data
+	}
+	return msg, nil
+}
+
+func defaultClientInfo() Implementation {
+	return Implementation{Name: httpclient.Product, Version: httpclient.Version()}
+}
@@ -0,0 +1,218 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/mcptest"
+)
+
+func connect(t *testing.T, def mcp.Definition) *Client {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := Connect(ctx, def, Options{})
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	result, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if result.ServerInfo.Name != "fake" || result.ProtocolVersion != ProtocolVersion {
+		t.Fatalf("unexpected initialize result: %+v", result)
+	}
+	return client
+}
+
+func httpServer(t *testing.T, server *mcptest.Server, legacy bool) mcp.Definition {
+	t.Helper()
+	handler := server.Handler()
+	if legacy {
+		handler = server.LegacyHandler()
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return mcp.Definition{Name: "fake", Transport: "http", URL: ts.URL + "/mcp"}
+}
+
+func TestClientTransports(t *testing.T) {
+	tests := []struct {
+		name string
+		def  func(t *testing.T) mcp.Definition
+	}{
+		{"stdio", func(t *testing.T) mcp.Definition { return stdioServer("plain") }},
+		{"stdio noise and blank lines", func(t *testing.T) mcp.Definition { return stdioServer("noisy") }},
+		{"stdio duplicate responses", func(t *testing.T) mcp.Definition { return stdioServer("duplicate") }},
+		{"streamable http json", func(t *testing.T) mcp.Definition { return httpServer(t, &mcptest.Server{}, false) }},
+		{"streamable http event stream", func(t *testing.T) mcp.Definition {
+			return httpServer(t, &mcptest.Server{EventStream: true}, false)
+		}},
+		{"legacy sse fallback", func(t *testing.T) mcp.Definition { return httpServer(t, &mcptest.Server{}, true) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := connect(t, tt.def(t))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			for i := 0; i < 3; i++ {
+				if err := client.Ping(ctx); err != nil {
+					t.Fatalf("Ping %d: %v", i, err)
+				}
+			}
+			tools, err := client.ListTools(ctx)
+			if err != nil {
+				t.Fatalf("ListTools: %v", err)
+			}
+			if names := toolNames(tools); names != "echo,sleep,cancelled" {
+				t.Fatalf("tools across pages = %s", names)
+			}
+			resources, err := client.ListResources(ctx)
+			if err != nil || len(resources) != 1 || resources[0].Name != "readme" {
+				t.Fatalf("ListResources = %+v, %v", resources, err)
+			}
+			prompts, err := client.ListPrompts(ctx)
+			if err != nil || len(prompts) != 1 || prompts[0].Name != "greet" {
+				t.Fatalf("ListPrompts = %+v, %v", prompts, err)
+			}
+			result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hi"})
+			if err != nil {
+				t.Fatalf("CallTool: %v", err)
+			}
+			if len(result.Content) != 1 || result.Content[0].Text != `{"text":"hi"}` {
+				t.Fatalf("CallTool content = %+v", result.Content)
+			}
+			var rpcErr *RPCError
+			if _, err := client.CallTool(ctx, "missing", nil); !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+				t.Fatalf("CallTool(missing) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClientCancellation(t *testing.T) {
+	tests := []struct {
+		name string
+		def  func(t *testing.T) mcp.Definition
+	}{
+		{"stdio", func(t *testing.T) mcp.Definition { return stdioServer("plain") }},
+		{"streamable http", func(t *testing.T) mcp.Definition { return httpServer(t, &mcptest.Server{}, false) }},
+		{"legacy sse", func(t *testing.T) mcp.Definition { return httpServer(t, &mcptest.Server{}, true) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := connect(t, tt.def(t))
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			_, err := client.CallTool(ctx, "sleep", nil)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("CallTool(sleep) error = %v, want deadline exceeded", err)
+			}
+
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			var cancelled string
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+				result, err := client.CallTool(ctx, "cancelled", nil)
+				if err != nil {
+					t.Fatalf("CallTool(cancelled): %v", err)
+				}
+				if cancelled = result.Content[0].Text; cancelled != "" {
+					break
+				}
+			}
+			var id int
+			if err := json.Unmarshal([]byte(cancelled), &id); err != nil || id == 0 {
+				t.Fatalf("server saw cancelled request ids %q, want the sleep request id", cancelled)
+			}
+		})
+	}
+}
+
+func TestClientTimesOutSilentServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := Connect(ctx, stdioServer("silent"), Options{})
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+	shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer shortCancel()
+	if _, err := client.Initialize(shortCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Initialize error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestDispatchDropsUnexpectedResponses(t *testing.T) {
+	client := &Client{pending: map[string]chan *message{}, done: make(chan struct{})}
+	reply := make(chan *message, 1)
+	client.pending["1"] = reply
+
+	delivered := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			client.dispatch(&message{JSONRPC: "2.0", ID: json.RawMessage("1"), Result: json.RawMessage("{}")})
+		}
+		client.dispatch(&message{JSONRPC: "2.0", ID: json.RawMessage("7"), Result: json.RawMessage("{}")})
+		close(delivered)
+	}()
+	select {
+	case <-delivered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("dispatch blocked on a duplicate response")
+	}
+	if len(reply) != 1 {
+		t.Fatalf("reply channel holds %d messages, want 1", len(reply))
+	}
+	if _, ok := client.pending["1"]; ok {
+		t.Fatal("pending entry kept after its response was delivered")
+	}
+}
+
+func TestReadSSE(t *testing.T) {
+	input := "event: endpoint\ndata: /messages\n\n: comment\ndata: {\"a\":\r\ndata: 1}\r\n\r\nevent: message\ndata: last"
+	var got []string
+	err := readSSE(strings.NewReader(input), func(event, data string) (bool, error) {
+		got = append(got, event+"|"+data)
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("readSSE: %v", err)
+	}
+	want := []string{"endpoint|/messages", "|{\"a\":\n1}"}
+	if strings.Join(got, ";") != strings.Join(want, ";") {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+}
+
+func toolNames(tools []Tool) string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestHTTPTransportLeavesSlowResponsesToTheContext(t *testing.T) {
+	transport, err := newHTTPTransport(mcp.Definition{Transport: "http", URL: "http://127.0.0.1/mcp"})
+	if err != nil {
+		t.Fatalf("newHTTPTransport: %v", err)
+	}
+	base, ok := transport.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("client transport is %T", transport.client.Transport)
+	}
+	if base.ResponseHeaderTimeout != 0 || transport.client.Timeout != 0 {
+		t.Fatalf("response header timeout %s, client timeout %s; a long tool call must only be bounded by its context", base.ResponseHeaderTimeout, transport.client.Timeout)
+	}
+}
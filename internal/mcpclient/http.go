@@ -0,0 +1,222 @@
+package mcpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-skill-manager/internal/httpclient"
+	"mcp-skill-manager/internal/mcp"
+)
+
+const (
+	sessionHeader         = "Mcp-Session-Id"
+	protocolVersionHeader = "MCP-Protocol-Version"
+	errorBodyLimit        = 8 * 1024
+	sessionCloseTimeout   = 5 * time.Second
+)
+
+type httpTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	deliver func(*message)
+	fail    func(error)
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+	legacy          *sseTransport
+}
+
+func newHTTPTransport(def mcp.Definition) (*httpTransport, error) {
+	base, err := httpclient.StreamingTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &httpTransport{url: def.URL, headers: def.Headers, client: &http.Client{Transport: base}}, nil
+}
+
+func (t *httpTransport) start(ctx context.Context, deliver func(*message), fail func(error)) error {
+	t.deliver = deliver
+	t.fail = fail
+	return nil
+}
+
+func (t *httpTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	t.protocolVersion = version
+	t.mu.Unlock()
+}
+
+func (t *httpTransport) send(ctx context.Context, msg *message) error {
+	if legacy := t.legacyTransport(); legacy != nil {
+		return legacy.send(ctx, msg)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.applyHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get(sessionHeader); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+	if msg.Method == "initialize" && isLegacyStatus(resp.StatusCode) {
+		return t.fallBackToSSE(ctx, msg, resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		return fmt.Errorf("http %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/event-stream":
+		return t.readEvents(resp.Body, msg)
+	case "application/json", "":
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			if msg.isRequest() {
+				return fmt.Errorf("empty response body")
+			}
+			return nil
+		}
+		return t.deliverPayload(body)
+	default:
+		return fmt.Errorf("unexpected content type %q", mediaType)
+	}
+}
+
+func (t *httpTransport) readEvents(body io.Reader, sent *message) error {
+	answered := false
+	err := readSSE(body, func(event, data string) (bool, error) {
+		if event != "" && event != "message" {
+			return false, nil
+		}
+		var err error
+		answered, err = t.deliverEvent(data, sent)
+		return answered, err
+	})
+	if err == nil && !answered && sent.isRequest() {
+		return fmt.Errorf("event stream closed before the response arrived")
+	}
+	return err
+}
+
+func (t *httpTransport) deliverEvent(data string, sent *message) (bool, error) {
+	var msg message
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		return false, fmt.Errorf("invalid event data: %w", err)
+	}
+	t.deliver(&msg)
+	return msg.isResponse() && bytes.Equal(msg.ID, sent.ID), nil
+}
+
+func (t *httpTransport) deliverPayload(body []byte) error {
+	body = bytes.TrimSpace(body)
+	if body[0] == '[' {
+		var batch []message
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		for i := range batch {
+			t.deliver(&batch[i])
+		}
+		return nil
+	}
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	t.deliver(&msg)
+	return nil
+}
+
+func (t *httpTransport) fallBackToSSE(ctx context.Context, msg *message, status string) error {
+	legacy := newSSETransport(t.url, t.headers, t.client)
+	if err := legacy.start(ctx, t.deliver, t.fail); err != nil {
+		return fmt.Errorf("http %s, and the HTTP+SSE fallback failed: %w", status, err)
+	}
+	t.mu.Lock()
+	t.legacy = legacy
+	t.mu.Unlock()
+	return legacy.send(ctx, msg)
+}
+
+func (t *httpTransport) legacyTransport() *sseTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.legacy
+}
+
+func isLegacyStatus(status int) bool {
+	return status == http.StatusBadRequest || status == http.StatusNotFound || status == http.StatusMethodNotAllowed
+}
+
+func (t *httpTransport) applyHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(sessionHeader, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(protocolVersionHeader, t.protocolVersion)
+	}
+}
+
+func (t *httpTransport) close() error {
+	if legacy := t.legacyTransport(); legacy != nil {
+		return legacy.close()
+	}
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCloseTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.applyHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,52 @@
+package mcpclient
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/mcptest"
+)
+
+const serverModeEnv = "MCPCLIENT_TEST_SERVER"
+
+func TestMain(m *testing.M) {
+	mode := os.Getenv(serverModeEnv)
+	if mode == "" {
+		os.Exit(m.Run())
+	}
+	server := &mcptest.Server{}
+	switch mode {
+	case "noisy":
+		server.Noise = true
+	case "duplicate":
+		server.Duplicate = true
+	case "silent":
+		server.Silent = true
+	case "sleep":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	case "grandchild":
+		child := exec.Command(os.Args[0], "-test.run=^$")
+		child.Env = append(os.Environ(), serverModeEnv+"=sleep")
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		if err := child.Start(); err != nil {
+			os.Exit(1)
+		}
+	}
+	server.Serve(os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+func stdioServer(mode string) mcp.Definition {
+	return mcp.Definition{
+		Name:      "fake",
+		Transport: "stdio",
+		Command:   os.Args[0],
+		Args:      []string{"-test.run=^$"},
+		Env:       map[string]string{serverModeEnv: mode},
+	}
+}
@@ -0,0 +1,184 @@
+package mcpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"mcp-skill-manager/internal/httpclient"
+)
+
+func readSSE(body io.Reader, handle func(event, data string) (bool, error)) error {
+	reader := bufio.NewReader(body)
+	var data strings.Builder
+	event := ""
+	for {
+		line, err := reader.ReadString('\n')
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case trimmed == "" && line != "":
+			if data.Len() > 0 {
+				stop, handleErr := handle(event, data.String())
+				if handleErr != nil || stop {
+					return handleErr
+				}
+			}
+			data.Reset()
+			event = ""
+		case strings.HasPrefix(trimmed, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(trimmed, "data:"), " "))
+		case strings.HasPrefix(trimmed, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(trimmed, "event:"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type sseTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	endpoint string
+	cancel   context.CancelFunc
+}
+
+func newSSETransport(url string, headers map[string]string, client *http.Client) *sseTransport {
+	return &sseTransport{url: url, headers: headers, client: client}
+}
+
+func (t *sseTransport) start(ctx context.Context, deliver func(*message), fail func(error)) error {
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, t.url, nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		cancel()
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		resp.Body.Close()
+		cancel()
+		return fmt.Errorf("http %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	endpoints := make(chan string, 1)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		err := readSSE(resp.Body, func(event, data string) (bool, error) {
+			switch event {
+			case "endpoint":
+				select {
+				case endpoints <- strings.TrimSpace(data):
+				default:
+				}
+			case "", "message":
+				var msg message
+				if err := json.Unmarshal([]byte(data), &msg); err == nil {
+					deliver(&msg)
+				}
+			}
+			return false, nil
+		})
+		resp.Body.Close()
+		if err == nil {
+			err = fmt.Errorf("event stream closed")
+		}
+		fail(fmt.Errorf("sse: %w", err))
+	}()
+
+	select {
+	case endpoint := <-endpoints:
+		resolved, err := resolveEndpoint(t.url, endpoint)
+		if err != nil {
+			cancel()
+			return err
+		}
+		t.endpoint = resolved
+		t.cancel = cancel
+		return nil
+	case <-closed:
+		cancel()
+		return fmt.Errorf("sse: event stream closed before the endpoint event")
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+}
+
+func (t *sseTransport) send(ctx context.Context, msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		return fmt.Errorf("http %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (t *sseTransport) close() error {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	return nil
+}
+
+func resolveEndpoint(base, endpoint string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("sse: invalid endpoint %q: %w", endpoint, err)
+	}
+	resolved := baseURL.ResolveReference(ref)
+	if resolved.Scheme != baseURL.Scheme || resolved.Host != baseURL.Host {
+		return "", fmt.Errorf("sse: endpoint %s is not on %s", resolved, baseURL.Host)
+	}
+	return resolved.String(), nil
+}
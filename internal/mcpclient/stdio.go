@@ -0,0 +1,157 @@
+package mcpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-skill-manager/internal/mcp"
+)
+
+const (
+	stderrTailSize = 4 * 1024
+	stdioStopWait  = 2 * time.Second
+)
+
+type stdioTransport struct {
+	def    mcp.Definition
+	stderr io.Writer
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	tail   *tailBuffer
+	exited chan struct{}
+
+	writeMu sync.Mutex
+}
+
+func newStdioTransport(def mcp.Definition, stderr io.Writer) *stdioTransport {
+	return &stdioTransport{def: def, stderr: stderr, tail: &tailBuffer{limit: stderrTailSize}}
+}
+
+func (t *stdioTransport) start(ctx context.Context, deliver func(*message), fail func(error)) error {
+	cmd := exec.Command(t.def.Command, t.def.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range t.def.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	if t.stderr != nil {
+		cmd.Stderr = io.MultiWriter(t.tail, t.stderr)
+	} else {
+		cmd.Stderr = t.tail
+	}
+	cmd.WaitDelay = stdioStopWait
+	setProcessGroup(cmd)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", t.def.Command, err)
+	}
+	t.cmd = cmd
+	t.stdin = stdin
+	t.stdout = stdout
+	t.exited = make(chan struct{})
+
+	go func() {
+		reader := bufio.NewReader(stdout)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				var msg message
+				if jsonErr := json.Unmarshal(line, &msg); jsonErr == nil {
+					deliver(&msg)
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		waitErr := cmd.Wait()
+		close(t.exited)
+		if waitErr == nil {
+			waitErr = fmt.Errorf("exit status 0")
+		}
+		fail(fmt.Errorf("server exited: %v%s", waitErr, t.tail.summary()))
+	}()
+	return nil
+}
+
+func (t *stdioTransport) send(ctx context.Context, msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(data); err != nil {
+		return fmt.Errorf("write to server: %w%s", err, t.tail.summary())
+	}
+	return nil
+}
+
+func (t *stdioTransport) close() error {
+	if t.cmd == nil {
+		return nil
+	}
+	t.stdin.Close()
+	select {
+	case <-t.exited:
+		return nil
+	case <-time.After(stdioStopWait):
+	}
+	killProcessGroup(t.cmd)
+	select {
+	case <-t.exited:
+		return nil
+	case <-time.After(stdioStopWait):
+	}
+	t.stdout.Close()
+	select {
+	case <-t.exited:
+		return nil
+	case <-time.After(stdioStopWait):
+		return fmt.Errorf("server %s did not exit", t.def.Command)
+	}
+}
+
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) summary() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	text := strings.TrimSpace(string(b.data))
+	if text == "" {
+		return ""
+	}
+	return "\nserver stderr:\n" + text
+}
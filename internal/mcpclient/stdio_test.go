@@ -0,0 +1,35 @@
+package mcpclient
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStdioCloseKillsGrandchildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are unix-only")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := Connect(ctx, stdioServer("grandchild"), Options{})
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	closed := make(chan error, 1)
+	started := time.Now()
+	go func() { closed <- client.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(4 * stdioStopWait):
+		t.Fatalf("Close still blocked after %s while a grandchild holds stdout and stderr", time.Since(started))
+	}
+}
@@ -0,0 +1,249 @@
+package mcptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const ProtocolVersion = "2025-06-18"
+
+type Server struct {
+	Silent      bool
+	Duplicate   bool
+	Noise       bool
+	EventStream bool
+
+	mu        sync.Mutex
+	cancelled []string
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *Server) Cancelled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cancelled...)
+}
+
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var writeMu sync.Mutex
+	write := func(resp *message) {
+		data, _ := json.Marshal(resp)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if s.Noise {
+			fmt.Fprint(out, "starting fake server...\n\n   \n")
+		}
+		count := 1
+		if s.Duplicate {
+			count = 2
+		}
+		for i := 0; i < count; i++ {
+			out.Write(append(data, '\n'))
+		}
+	}
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		if resp := s.handle(&msg); resp != nil {
+			write(resp)
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPost:
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var msg message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if msg.Method == "initialize" {
+			w.Header().Set("Mcp-Session-Id", "session-1")
+		} else if r.Header.Get("Mcp-Session-Id") != "session-1" {
+			http.Error(w, "missing session", http.StatusBadRequest)
+			return
+		}
+		resp := s.handle(&msg)
+		if len(msg.ID) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if resp == nil {
+			<-r.Context().Done()
+			return
+		}
+		data, _ := json.Marshal(resp)
+		if s.EventStream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+func (s *Server) LegacyHandler() http.Handler {
+	var (
+		mu      sync.Mutex
+		streams []chan []byte
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			stream := make(chan []byte, 16)
+			mu.Lock()
+			streams = append(streams, stream)
+			mu.Unlock()
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: endpoint\ndata: %s/messages?session=1\n\n", strings.TrimSuffix(r.URL.Path, "/"))
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case data := <-stream:
+					fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/messages"):
+			var msg message
+			if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if resp := s.handle(&msg); resp != nil {
+				data, _ := json.Marshal(resp)
+				mu.Lock()
+				for _, stream := range streams {
+					stream <- data
+				}
+				mu.Unlock()
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func (s *Server) handle(msg *message) *message {
+	if msg.Method == "notifications/cancelled" {
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		s.mu.Lock()
+		s.cancelled = append(s.cancelled, string(params.RequestID))
+		s.mu.Unlock()
+		return nil
+	}
+	if len(msg.ID) == 0 || s.Silent {
+		return nil
+	}
+	resp := &message{JSONRPC: "2.0", ID: msg.ID}
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		if params.ProtocolVersion == "" {
+			params.ProtocolVersion = ProtocolVersion
+		}
+		resp.Result = map[string]any{
+			"protocolVersion": params.ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{}, "prompts": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "1.0.0"},
+		}
+	case "ping":
+		resp.Result = map[string]any{}
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		if params.Cursor == "" {
+			resp.Result = map[string]any{
+				"tools":      []any{tool("echo", "Echo the arguments back")},
+				"nextCursor": "page-2",
+			}
+		} else {
+			resp.Result = map[string]any{"tools": []any{tool("sleep", "Never answers"), tool("cancelled", "List cancelled request ids")}}
+		}
+	case "resources/list":
+		resp.Result = map[string]any{"resources": []any{map[string]any{"uri": "file:///readme.md", "name": "readme"}}}
+	case "prompts/list":
+		resp.Result = map[string]any{"prompts": []any{map[string]any{"name": "greet"}}}
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		switch params.Name {
+		case "echo":
+			data, _ := json.Marshal(params.Arguments)
+			resp.Result = textResult(string(data))
+		case "sleep":
+			return nil
+		case "cancelled":
+			resp.Result = textResult(strings.Join(s.Cancelled(), ","))
+		default:
+			resp.Error = &rpcError{Code: -32602, Message: "unknown tool: " + params.Name}
+		}
+	default:
+		resp.Error = &rpcError{Code: -32601, Message: "method not found: " + msg.Method}
+	}
+	return resp
+}
+
+func tool(name, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"description": description,
+		"inputSchema": map[string]any{"type": "object"},
+	}
+}
+
+func textResult(text string) map[string]any {
+	return map[string]any{"content": []any{map[string]any{"type": "text", "text": text}}}
+}
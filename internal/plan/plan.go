@@ -0,0 +1,238 @@
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Kind string
+
+const (
+	Create Kind = "create"
+	Modify Kind = "modify"
+	Delete Kind = "delete"
+	Run    Kind = "run"
+)
+
+type Change struct {
+	Kind    Kind
+	Path    string
+	Dir     bool
+	Source  string
+	Command string
+	Before  []byte
+	After   []byte
+}
+
+type recorder struct {
+	passthrough []string
+	changes     []*Change
+	byPath      map[string]*Change
+}
+
+var (
+	mu     sync.Mutex
+	active *recorder
+)
+
+func Start(passthrough ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	rec := &recorder{byPath: map[string]*Change{}}
+	for _, path := range passthrough {
+		if path = normalize(path); path != "" {
+			rec.passthrough = append(rec.passthrough, path)
+		}
+	}
+	active = rec
+}
+
+func Stop() []Change {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return nil
+	}
+	var changes []Change
+	for _, change := range active.changes {
+		if change.Kind == Modify && !change.Dir && bytes.Equal(change.Before, change.After) {
+			continue
+		}
+		changes = append(changes, *change)
+	}
+	active = nil
+	return changes
+}
+
+func Active() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return active != nil
+}
+
+func Intercepts(path string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return false
+	}
+	path = normalize(path)
+	for _, root := range active.passthrough {
+		if within(root, path) {
+			return false
+		}
+	}
+	return true
+}
+
+func RecordWrite(path string, data []byte) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return fmt.Errorf("no plan in progress")
+	}
+	path = normalize(path)
+	after := append([]byte(nil), data...)
+	if change, ok := active.byPath[path]; ok {
+		switch {
+		case change.Dir:
+			return fmt.Errorf("plan: %s is a directory", path)
+		case change.Kind == Delete:
+			change.Kind = Modify
+		}
+		change.After = after
+		return nil
+	}
+	before, err := os.ReadFile(path)
+	kind := Modify
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		kind = Create
+	}
+	active.add(&Change{Kind: kind, Path: path, Before: before, After: after})
+	return nil
+}
+
+func RecordRemove(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return fmt.Errorf("no plan in progress")
+	}
+	path = normalize(path)
+	if change, ok := active.byPath[path]; ok {
+		if change.Kind == Create {
+			active.drop(path)
+			return nil
+		}
+		change.Kind = Delete
+		change.Source = ""
+		change.After = nil
+		return nil
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	change := &Change{Kind: Delete, Path: path, Dir: info.IsDir()}
+	if !change.Dir {
+		change.Before, _ = os.ReadFile(path)
+	}
+	active.add(change)
+	return nil
+}
+
+func RecordCopy(src, dst string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return fmt.Errorf("no plan in progress")
+	}
+	dst = normalize(dst)
+	src = normalize(src)
+	if change, ok := active.byPath[dst]; ok {
+		if change.Kind == Delete {
+			change.Kind = Modify
+		}
+		change.Dir = true
+		change.Source = src
+		return nil
+	}
+	kind := Create
+	if _, err := os.Stat(dst); err == nil {
+		kind = Modify
+	}
+	active.add(&Change{Kind: kind, Path: dst, Dir: true, Source: src})
+	return nil
+}
+
+func RecordCommand(dir, command string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return
+	}
+	active.changes = append(active.changes, &Change{Kind: Run, Path: normalize(dir), Command: command})
+}
+
+func Staged(path string) ([]byte, bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return nil, false, nil
+	}
+	change, ok := active.byPath[normalize(path)]
+	if !ok || change.Dir {
+		return nil, false, nil
+	}
+	if change.Kind == Delete {
+		return nil, true, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), change.After...), true, nil
+}
+
+func (r *recorder) add(change *Change) {
+	r.changes = append(r.changes, change)
+	r.byPath[change.Path] = change
+}
+
+func (r *recorder) drop(path string) {
+	change := r.byPath[path]
+	delete(r.byPath, path)
+	for i, item := range r.changes {
+		if item == change {
+			r.changes = append(r.changes[:i], r.changes[i+1:]...)
+			return
+		}
+	}
+}
+
+func normalize(path string) string {
+	if strings.TrimSpace(path) == "" {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Clean(path)
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))
+}
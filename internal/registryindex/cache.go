@@ -0,0 +1,97 @@
+package registryindex
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mcp-skill-manager/internal/safefile"
+)
+
+const cacheDir = ".cache"
+
+type cacheEntry struct {
+	Repo       string     `json:"repo"`
+	Branch     string     `json:"branch,omitempty"`
+	FetchedAt  string     `json:"fetchedAt"`
+	Validators Validators `json:"validators"`
+}
+
+var revalidated = map[string]bool{}
+
+func FetchCached(registry, file string, revalidate bool) ([]byte, error) {
+	reg, err := registryNamed(registry)
+	if err != nil {
+		return nil, err
+	}
+	return reg.FetchCached(file, revalidate)
+}
+
+func (r Registry) FetchCached(file string, revalidate bool) ([]byte, error) {
+	dir, err := r.Dir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, cacheDir, filepath.FromSlash(file))
+	entry, cached, hasCache := r.loadCached(path)
+	if hasCache {
+		if r.offlineSkipped() {
+			return cached, nil
+		}
+		forced := revalidate || (refresh && !revalidated[path])
+		if fetchedAt, err := time.Parse(time.RFC3339, entry.FetchedAt); err == nil && !forced && time.Since(fetchedAt) <= registryTTL() {
+			return cached, nil
+		}
+	}
+
+	source, err := r.Source()
+	if err != nil {
+		return nil, err
+	}
+	var validators Validators
+	if hasCache {
+		validators = entry.Validators
+	}
+	result, err := source.Fetch(file, validators)
+	if err != nil {
+		return nil, err
+	}
+	revalidated[path] = true
+	data := result.Data
+	if result.NotModified {
+		data = cached
+	} else if err := safefile.WriteFile(path, data, 0o644); err != nil {
+		return nil, err
+	}
+	entry = cacheEntry{
+		Repo:       r.URL,
+		Branch:     r.Branch,
+		FetchedAt:  time.Now().UTC().Format(time.RFC3339),
+		Validators: result.Validators,
+	}
+	sidecar, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := safefile.WriteFile(path+".cache.json", sidecar, 0o644); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r Registry) loadCached(path string) (cacheEntry, []byte, bool) {
+	raw, err := os.ReadFile(path + ".cache.json")
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil || entry.Repo != r.URL || entry.Branch != r.Branch {
+		return cacheEntry{}, nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	return entry, data, true
+}
@@ -3,6 +3,7 @@ package registryindex
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,39 +14,42 @@ const (
 	skillIndex    = "index.skill.json"
 	mcpIndex      = "index.mcp.json"
 	metaFile      = "index.meta.json"
-	syncTTL       = 5 * time.Minute
+	defaultTTL    = 5 * time.Minute
 )
 
-func registryRepo() string {
-	if value := strings.TrimSpace(os.Getenv("MCP_REGISTRY_REPO")); value != "" {
-		return value
-	}
-	return defaultRepo
-}
+var (
+	refresh bool
+	synced  = map[string]bool{}
+)
 
-func registryBranch() string {
-	if value := strings.TrimSpace(os.Getenv("MCP_REGISTRY_BRANCH")); value != "" {
-		return value
-	}
-	return defaultBranch
+func SetRefresh(value bool) {
+	refresh = value
 }
 
-func rawBaseURL() (string, error) {
-	repo := strings.TrimSpace(registryRepo())
-	if repo == "" {
-		return "", fmt.Errorf("registry repo is empty")
+func registryTTL() time.Duration {
+	value := strings.TrimSpace(os.Getenv("MCP_REGISTRY_TTL"))
+	if value == "" {
+		return defaultTTL
 	}
-	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") {
-		repo = strings.TrimPrefix(repo, "https://github.com/")
-		repo = strings.TrimPrefix(repo, "http://github.com/")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
 	}
-	repo = strings.TrimSuffix(repo, ".git")
-	if strings.Count(repo, "/") != 1 {
-		return "", fmt.Errorf("invalid registry repo: %s", repo)
+	if ttl, err := time.ParseDuration(value); err == nil && ttl >= 0 {
+		return ttl
 	}
-	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/", repo, registryBranch()), nil
+	return defaultTTL
 }
 
-func RawBaseURL() (string, error) {
-	return rawBaseURL()
+func registryNamed(name string) (Registry, error) {
+	if name == "" {
+		name = DefaultRegistry
+	}
+	reg, ok, err := FindRegistry(name)
+	if err != nil {
+		return Registry{}, err
+	}
+	if !ok {
+		return Registry{}, fmt.Errorf("unknown registry: %s", name)
+	}
+	return reg, nil
 }
@@ -8,51 +8,104 @@ import (
 	"path/filepath"
 	"strings"
 
+	"mcp-skill-manager/internal/credentials"
 	"mcp-skill-manager/internal/installer"
 	"mcp-skill-manager/internal/mcp"
+	"mcp-skill-manager/internal/plan"
 )
 
+type StagedSkill struct {
+	Path string
+
+	entry   SkillEntry
+	tempDir string
+}
+
 func SyncSkill(entry SkillEntry) error {
+	staged, err := StageSkill(entry)
+	if err != nil {
+		return err
+	}
+	defer staged.Cleanup()
+	return staged.Commit()
+}
+
+func StageSkill(entry SkillEntry) (*StagedSkill, error) {
 	if strings.TrimSpace(entry.Name) == "" {
-		return fmt.Errorf("invalid skill entry: missing name")
+		return nil, fmt.Errorf("invalid skill entry: missing name")
 	}
 
-	needs, err := needsUpdate("skill", entry.Name, entry.Head)
+	reg, err := registryNamed(entry.Registry)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	entry.Registry = reg.Name
+	cachedPath, err := SkillPathInStore(reg.Name, entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	needs, err := needsUpdate("skill", reg.Name, entry.Name, entry.Head)
+	if err != nil {
+		return nil, err
 	}
 	if !needs {
-		return nil
+		return &StagedSkill{Path: cachedPath, entry: entry}, nil
+	}
+	if reg.offlineSkipped() {
+		if CachedEntryExists("skill", reg.Name, entry.Name) {
+			return &StagedSkill{Path: cachedPath, entry: entry}, nil
+		}
+		return nil, OfflineError{What: "skill " + entry.Name + " (not in the local store)"}
 	}
 
 	tempDir, err := os.MkdirTemp("", "mcp-skill-registry-*")
 	if err != nil {
-		return err
+		return nil, err
+	}
+	source, err := reg.Source()
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, err
+	}
+	path := filepath.Join(tempDir, entry.Name)
+	if err := source.FetchSkill(entry.Name, path); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, err
 	}
-	defer os.RemoveAll(tempDir)
+	return &StagedSkill{Path: path, entry: entry, tempDir: tempDir}, nil
+}
 
-	repo := registryRepo()
-	if strings.TrimSpace(repo) == "" {
-		return fmt.Errorf("registry repo is empty")
+func (s *StagedSkill) Commit() error {
+	if s.tempDir == "" {
+		return nil
 	}
-	if err := gitClone(repo, tempDir); err != nil {
+	cachedPath, err := SkillPathInStore(s.entry.Registry, s.entry.Name)
+	if err != nil {
 		return err
 	}
-
-	path := filepath.Join(tempDir, "skill", entry.Name)
-	if _, err := os.Stat(path); err != nil {
-		return fmt.Errorf("skill path not found: skill/%s", entry.Name)
+	if err := installer.ReplaceDir(s.Path, cachedPath); err != nil {
+		return err
 	}
-	if _, err := installer.CacheSkillDir(path); err != nil {
+	if err := SaveLocalRecord("skill", LocalRecord{
+		Name:      s.entry.Name,
+		Registry:  s.entry.Registry,
+		Repo:      s.entry.Repo,
+		Path:      s.entry.Path,
+		Head:      s.entry.Head,
+		UpdatedAt: s.entry.UpdatedAt,
+	}); err != nil {
 		return err
 	}
-	return SaveLocalRecord("skill", LocalRecord{
-		Name:      entry.Name,
-		Repo:      entry.Repo,
-		Path:      entry.Path,
-		Head:      entry.Head,
-		UpdatedAt: entry.UpdatedAt,
-	})
+	if !plan.Active() {
+		s.Path = cachedPath
+	}
+	return nil
+}
+
+func (s *StagedSkill) Cleanup() {
+	if s.tempDir != "" {
+		os.RemoveAll(s.tempDir)
+	}
 }
 
 func SyncMCP(entry MCPEntry) error {
@@ -60,7 +113,11 @@ func SyncMCP(entry MCPEntry) error {
 		return fmt.Errorf("invalid mcp entry: missing name")
 	}
 
-	needs, err := needsUpdate("mcp", entry.Name, entry.Head)
+	reg, err := registryNamed(entry.Registry)
+	if err != nil {
+		return err
+	}
+	needs, err := needsUpdate("mcp", reg.Name, entry.Name, entry.Head)
 	if err != nil {
 		return err
 	}
@@ -88,6 +145,7 @@ func SyncMCP(entry MCPEntry) error {
 	}
 	return SaveLocalRecord("mcp", LocalRecord{
 		Name:      entry.Name,
+		Registry:  reg.Name,
 		Repo:      entry.Repo,
 		Path:      entry.Path,
 		Head:      entry.Head,
@@ -95,29 +153,27 @@ func SyncMCP(entry MCPEntry) error {
 	})
 }
 
-func needsUpdate(kind, name, head string) (bool, error) {
-	record, ok, err := LoadLocalRecord(kind, name)
+func needsUpdate(kind, registry, name, head string) (bool, error) {
+	record, ok, err := LoadRegistryRecord(kind, registry, name)
 	if err != nil {
 		return true, err
 	}
-	if !ok {
+	if !ok || record.Head != head {
 		return true, nil
 	}
-	if record.Head != head {
-		return true, nil
-	}
-	if !cachedEntryExists(kind, name) {
+	if !CachedEntryExists(kind, registry, name) {
 		return true, nil
 	}
 	return false, nil
 }
 
-func gitClone(repoURL, dest string) error {
-	url, err := normalizeRepoURL(repoURL)
-	if err != nil {
-		return err
+func gitCloneBranch(repoURL, branch, dest string, token credentials.Token) error {
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
 	}
-	cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", url, dest)
+	cmd := exec.Command("git", append(args, repoURL, dest)...)
+	cmd.Env = credentials.GitEnv(repoURL, token)
 	var output bytes.Buffer
 	cmd.Stdout = &output
 	cmd.Stderr = &output
@@ -126,29 +182,15 @@ func gitClone(repoURL, dest string) error {
 		if msg == "" {
 			msg = err.Error()
 		}
-		return fmt.Errorf("git clone failed: %s", msg)
+		return fmt.Errorf("git clone failed: %s", credentials.Redact(msg, token))
 	}
 	return nil
 }
 
-func normalizeRepoURL(repo string) (string, error) {
-	repo = strings.TrimSpace(repo)
-	if repo == "" {
-		return "", fmt.Errorf("repository is required")
-	}
-	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "git@") {
-		return repo, nil
-	}
-	if strings.Count(repo, "/") == 1 {
-		return "https://github.com/" + repo + ".git", nil
-	}
-	return "", fmt.Errorf("unsupported repository format: %s", repo)
-}
-
-func cachedEntryExists(kind, name string) bool {
+func CachedEntryExists(kind, registry, name string) bool {
 	switch kind {
 	case "skill":
-		path, err := SkillPathInStore(name)
+		path, err := SkillPathInStore(registry, name)
 		if err != nil {
 			return false
 		}
@@ -11,43 +11,111 @@ import (
 )
 
 func LoadSkillIndex() (SkillIndex, error) {
-	root, err := installer.LocalStoreRoot()
+	registries, err := Registries()
 	if err != nil {
 		return SkillIndex{}, err
 	}
-	data, err := os.ReadFile(filepath.Join(root, skillIndex))
+	var merged SkillIndex
+	for _, reg := range registries {
+		index, err := reg.LoadSkillIndex()
+		if IsOffline(err) {
+			continue
+		}
+		if err != nil {
+			return SkillIndex{}, registryError(registries, reg, err)
+		}
+		if merged.GeneratedAt == "" {
+			merged.GeneratedAt = index.GeneratedAt
+		}
+		merged.Skills = append(merged.Skills, index.Skills...)
+	}
+	return merged, nil
+}
+
+func LoadMCPIndex() (MCPIndex, error) {
+	registries, err := Registries()
+	if err != nil {
+		return MCPIndex{}, err
+	}
+	var merged MCPIndex
+	for _, reg := range registries {
+		index, err := reg.LoadMCPIndex()
+		if IsOffline(err) {
+			continue
+		}
+		if err != nil {
+			return MCPIndex{}, registryError(registries, reg, err)
+		}
+		if merged.GeneratedAt == "" {
+			merged.GeneratedAt = index.GeneratedAt
+		}
+		merged.MCP = append(merged.MCP, index.MCP...)
+	}
+	return merged, nil
+}
+
+func (r Registry) LoadSkillIndex() (SkillIndex, error) {
+	dir, err := r.Dir()
 	if err != nil {
 		return SkillIndex{}, err
 	}
+	data, err := os.ReadFile(filepath.Join(dir, skillIndex))
+	if err != nil {
+		if os.IsNotExist(err) && r.offlineSkipped() {
+			return SkillIndex{}, OfflineError{What: "registry index " + r.Name}
+		}
+		return SkillIndex{}, err
+	}
 	var index SkillIndex
 	if err := json.Unmarshal(data, &index); err != nil {
 		return SkillIndex{}, err
 	}
+	for i := range index.Skills {
+		index.Skills[i].Registry = r.Name
+	}
 	return index, nil
 }
 
-func LoadMCPIndex() (MCPIndex, error) {
-	root, err := installer.LocalStoreRoot()
+func (r Registry) LoadMCPIndex() (MCPIndex, error) {
+	dir, err := r.Dir()
 	if err != nil {
 		return MCPIndex{}, err
 	}
-	data, err := os.ReadFile(filepath.Join(root, mcpIndex))
+	data, err := os.ReadFile(filepath.Join(dir, mcpIndex))
 	if err != nil {
+		if os.IsNotExist(err) && r.offlineSkipped() {
+			return MCPIndex{}, OfflineError{What: "registry index " + r.Name}
+		}
 		return MCPIndex{}, err
 	}
 	var index MCPIndex
 	if err := json.Unmarshal(data, &index); err != nil {
 		return MCPIndex{}, err
 	}
+	if len(index.MCP) == 0 {
+		index.MCP = index.Servers
+	}
+	index.Servers = nil
+	for i := range index.MCP {
+		index.MCP[i].Registry = r.Name
+	}
 	return index, nil
 }
 
 func FindSkill(name string) (SkillEntry, bool, error) {
+	registries, err := Registries()
+	if err != nil {
+		return SkillEntry{}, false, err
+	}
+	registry, name := splitQualified(registries, name)
 	index, err := LoadSkillIndex()
 	if err != nil {
 		return SkillEntry{}, false, err
 	}
 	for _, entry := range index.Skills {
+		if registry != "" && entry.Registry != registry {
+			continue
+		}
 		if strings.EqualFold(entry.Name, name) {
 			return entry, true, nil
 		}
@@ -56,15 +124,19 @@ func FindSkill(name string) (SkillEntry, bool, error) {
 }
 
 func FindMCP(name string) (MCPEntry, bool, error) {
-	index, err := LoadMCPIndex()
+	registries, err := Registries()
 	if err != nil {
 		return MCPEntry{}, false, err
 	}
-	candidates := index.MCP
-	if len(candidates) == 0 {
-		candidates = index.Servers
+	registry, name := splitQualified(registries, name)
+	index, err := LoadMCPIndex()
+	if err != nil {
+		return MCPEntry{}, false, err
 	}
-	for _, entry := range candidates {
+	for _, entry := range index.MCP {
+		if registry != "" && entry.Registry != registry {
+			continue
+		}
 		if strings.EqualFold(entry.Name, name) {
 			return entry, true, nil
 		}
@@ -72,14 +144,68 @@ func FindMCP(name string) (MCPEntry, bool, error) {
 	return MCPEntry{}, false, nil
 }
 
-func SkillPathInStore(name string) (string, error) {
-	root, err := installer.LocalSkillStore()
+func SkillPathInStore(registry, name string) (string, error) {
+	root, err := StoreDir(registry, "skill")
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(root, name), nil
 }
 
+func MCPRepoInStore(registry, name string) (string, error) {
+	root, err := StoreDir(registry, "mcp")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, name), nil
+}
+
+func StoreDirs(kind string) ([]string, error) {
+	dir, err := StoreDir(DefaultRegistry, kind)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{dir}
+	registries, err := Registries()
+	if err != nil {
+		return nil, err
+	}
+	for _, reg := range registries {
+		if reg.Name == DefaultRegistry {
+			continue
+		}
+		dir, err := StoreDir(reg.Name, kind)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+func StoreRoots() ([]string, error) {
+	skillDirs, err := StoreDirs("skill")
+	if err != nil {
+		return nil, err
+	}
+	mcpDirs, err := StoreDirs("mcp")
+	if err != nil {
+		return nil, err
+	}
+	return append(skillDirs, mcpDirs...), nil
+}
+
+func StoreDir(registry, kind string) (string, error) {
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	dir, err := Registry{Name: registry}.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, kind), nil
+}
+
 func MCPPathInStore(name string) (string, error) {
 	root, err := installer.LocalMcpStore()
 	if err != nil {
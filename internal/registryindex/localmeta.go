@@ -4,24 +4,118 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
-	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
 )
 
 type LocalRecord struct {
 	Name      string `json:"name"`
+	Registry  string `json:"registry,omitempty"`
 	Repo      string `json:"repo"`
 	Path      string `json:"path"`
 	Head      string `json:"head"`
 	UpdatedAt string `json:"updatedAt,omitempty"`
+	SyncedAt  string `json:"syncedAt,omitempty"`
+}
+
+type RecordRef struct {
+	Registry string
+	Name     string
+}
+
+type recordDir struct {
+	registry string
+	path     string
 }
 
 func LoadLocalRecord(kind, name string) (LocalRecord, bool, error) {
-	path, err := localRecordPath(kind, name)
+	dirs, err := recordDirs(kind)
 	if err != nil {
 		return LocalRecord{}, false, err
 	}
-	data, err := os.ReadFile(path)
+	var latest LocalRecord
+	found := false
+	for _, dir := range dirs {
+		record, ok, err := readRecord(dir, name)
+		if err != nil {
+			return LocalRecord{}, false, err
+		}
+		if ok && (!found || record.SyncedAt > latest.SyncedAt) {
+			latest, found = record, true
+		}
+	}
+	return latest, found, nil
+}
+
+func LoadRegistryRecord(kind, registry, name string) (LocalRecord, bool, error) {
+	dir, err := registryRecordDir(kind, registry)
+	if err != nil {
+		return LocalRecord{}, false, err
+	}
+	return readRecord(dir, name)
+}
+
+func SaveLocalRecord(kind string, record LocalRecord) error {
+	if record.Registry == "" {
+		record.Registry = DefaultRegistry
+	}
+	if record.SyncedAt == "" {
+		record.SyncedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	dir, err := registryRecordDir(kind, record.Registry)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(filepath.Join(dir.path, record.Name+".json"), data, 0o644)
+}
+
+func LocalRecordRefs(kind string) ([]RecordRef, error) {
+	dirs, err := recordDirs(kind)
+	if err != nil {
+		return nil, err
+	}
+	var refs []RecordRef
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			refs = append(refs, RecordRef{Registry: dir.registry, Name: strings.TrimSuffix(entry.Name(), ".json")})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Registry < refs[j].Registry
+	})
+	return refs, nil
+}
+
+func RemoveLocalRecord(kind, registry, name string) error {
+	dir, err := registryRecordDir(kind, registry)
+	if err != nil {
+		return err
+	}
+	return removeIfExists(filepath.Join(dir.path, name+".json"))
+}
+
+func readRecord(dir recordDir, name string) (LocalRecord, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir.path, name+".json"))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return LocalRecord{}, false, nil
@@ -32,32 +126,47 @@ func LoadLocalRecord(kind, name string) (LocalRecord, bool, error) {
 	if err := json.Unmarshal(data, &record); err != nil {
 		return LocalRecord{}, false, err
 	}
+	record.Registry = dir.registry
 	return record, true, nil
 }
 
-func LocalRecordFor(kind, name string) (LocalRecord, bool, error) {
-	return LoadLocalRecord(kind, name)
+func registryRecordDir(kind, registry string) (recordDir, error) {
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	dir, err := Registry{Name: registry}.Dir()
+	if err != nil {
+		return recordDir{}, err
+	}
+	return recordDir{registry: registry, path: filepath.Join(dir, ".meta", kind)}, nil
 }
 
-func SaveLocalRecord(kind string, record LocalRecord) error {
-	path, err := localRecordPath(kind, record.Name)
+func recordDirs(kind string) ([]recordDir, error) {
+	dir, err := registryRecordDir(kind, DefaultRegistry)
 	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(record, "", "  ")
+	dirs := []recordDir{dir}
+	registries, err := Registries()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, reg := range registries {
+		if reg.Name == DefaultRegistry {
+			continue
+		}
+		dir, err := registryRecordDir(kind, reg.Name)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
 	}
-	return os.WriteFile(path, data, 0o644)
+	return dirs, nil
 }
 
-func localRecordPath(kind, name string) (string, error) {
-	root, err := installer.LocalStoreRoot()
-	if err != nil {
-		return "", err
+func removeIfExists(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return nil
 	}
-	return filepath.Join(root, ".meta", kind, name+".json"), nil
+	return safefile.RemoveAll(path)
 }
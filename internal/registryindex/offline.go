@@ -0,0 +1,113 @@
+package registryindex
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var offline bool
+
+type OfflineError struct {
+	What string
+}
+
+func (e OfflineError) Error() string {
+	return e.What + " is not available offline"
+}
+
+func IsOffline(err error) bool {
+	var offlineErr OfflineError
+	return errors.As(err, &offlineErr)
+}
+
+func SetOffline(value bool) {
+	offline = value
+}
+
+func Offline() bool {
+	if offline {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("MCP_SKILL_OFFLINE"))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+func SkillAvailableOffline(entry SkillEntry) bool {
+	return CachedEntryExists("skill", entry.Registry, entry.Name)
+}
+
+func MCPAvailableOffline(entry MCPEntry) bool {
+	if isHTTPEntry(entry) {
+		return true
+	}
+	path, err := MCPRepoInStore(entry.Registry, entry.Name)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func CachedSkillEntry(entry SkillEntry) SkillEntry {
+	if record, ok := cachedRecord("skill", entry.Registry, entry.Name); ok && CachedEntryExists("skill", entry.Registry, entry.Name) {
+		entry.Head, entry.UpdatedAt = record.Head, record.UpdatedAt
+	}
+	return entry
+}
+
+func CachedMCPEntry(entry MCPEntry) MCPEntry {
+	if isHTTPEntry(entry) {
+		return entry
+	}
+	if record, ok := cachedRecord("mcp", entry.Registry, entry.Name); ok && MCPAvailableOffline(entry) {
+		entry.Head, entry.UpdatedAt = record.Head, record.UpdatedAt
+	}
+	return entry
+}
+
+func cachedRecord(kind, registry, name string) (LocalRecord, bool) {
+	reg, err := registryNamed(registry)
+	if err != nil || !reg.offlineSkipped() {
+		return LocalRecord{}, false
+	}
+	record, ok, err := LoadRegistryRecord(kind, reg.Name, name)
+	if err != nil || !ok {
+		return LocalRecord{}, false
+	}
+	return record, true
+}
+
+func isHTTPEntry(entry MCPEntry) bool {
+	entryType := strings.ToLower(strings.TrimSpace(entry.Type))
+	return entryType == "http" || (entryType == "" && strings.TrimSpace(entry.URL) != "")
+}
+
+func (r Registry) offlineSkipped() bool {
+	if !Offline() {
+		return false
+	}
+	kind, err := r.SourceType()
+	return err != nil || kind != SourceLocal
+}
+
+func ensureCachedIndexes(registries []Registry) error {
+	for _, reg := range registries {
+		dir, err := reg.Dir()
+		if err != nil {
+			return err
+		}
+		if fileExists(filepath.Join(dir, skillIndex)) || fileExists(filepath.Join(dir, mcpIndex)) {
+			return nil
+		}
+		if !reg.offlineSkipped() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no cached registry index in ~/.mcp-skill; run once online or copy ~/.mcp-skill from a connected machine")
+}
@@ -0,0 +1,302 @@
+package registryindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
+)
+
+const (
+	DefaultRegistry = "default"
+	registriesFile  = "registries.json"
+	registriesDir   = "registries"
+)
+
+var (
+	registryNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	envNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+type Registry struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Type     string `json:"type,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Priority int    `json:"priority"`
+	TokenEnv string `json:"tokenEnv,omitempty"`
+}
+
+type registriesConfig struct {
+	Registries []Registry `json:"registries"`
+}
+
+func Registries() ([]Registry, error) {
+	configured, err := loadRegistries()
+	if err != nil {
+		return nil, err
+	}
+	registries := make([]Registry, 0, len(configured))
+	for _, reg := range configured {
+		registries = append(registries, reg.withDefaults())
+	}
+	sort.SliceStable(registries, func(i, j int) bool {
+		return registries[i].Priority > registries[j].Priority
+	})
+	return registries, nil
+}
+
+func FindRegistry(name string) (Registry, bool, error) {
+	registries, err := Registries()
+	if err != nil {
+		return Registry{}, false, err
+	}
+	for _, reg := range registries {
+		if reg.Name == name {
+			return reg, true, nil
+		}
+	}
+	return Registry{}, false, nil
+}
+
+func AddRegistry(reg Registry) error {
+	reg.Name = strings.TrimSpace(reg.Name)
+	reg.URL = strings.TrimSpace(reg.URL)
+	reg.Type = strings.ToLower(strings.TrimSpace(reg.Type))
+	reg.Branch = strings.TrimSpace(reg.Branch)
+	reg.TokenEnv = strings.TrimPrefix(strings.TrimSpace(reg.TokenEnv), "$")
+	if err := ValidateRegistryName(reg.Name); err != nil {
+		return err
+	}
+	if reg.TokenEnv != "" && !envNamePattern.MatchString(reg.TokenEnv) {
+		return fmt.Errorf("invalid token variable name %q", reg.TokenEnv)
+	}
+	kind, err := reg.SourceType()
+	if err != nil {
+		return err
+	}
+	if kind == SourceLocal && !strings.HasPrefix(reg.URL, "file://") {
+		if reg.URL, err = localRoot(reg.URL); err != nil {
+			return err
+		}
+	}
+	if _, err := reg.withDefaults().Source(); err != nil {
+		return err
+	}
+	path, err := registriesPath()
+	if err != nil {
+		return err
+	}
+	return safefile.WithLock(path, func() error {
+		registries, err := loadRegistries()
+		if err != nil {
+			return err
+		}
+		for _, existing := range registries {
+			if existing.Name == reg.Name {
+				return fmt.Errorf("registry %s already exists", reg.Name)
+			}
+		}
+		return saveRegistries(append(registries, reg))
+	})
+}
+
+func RemoveRegistry(name string) (bool, error) {
+	path, err := registriesPath()
+	if err != nil {
+		return false, err
+	}
+	var removed *Registry
+	err = safefile.WithLock(path, func() error {
+		registries, err := loadRegistries()
+		if err != nil {
+			return err
+		}
+		kept := make([]Registry, 0, len(registries))
+		for _, reg := range registries {
+			if reg.Name == name {
+				reg := reg
+				removed = &reg
+				continue
+			}
+			kept = append(kept, reg)
+		}
+		if removed == nil {
+			return nil
+		}
+		return saveRegistries(kept)
+	})
+	if err != nil || removed == nil {
+		return false, err
+	}
+	var paths []string
+	if removed.Name == DefaultRegistry {
+		paths, err = removed.cachePaths()
+	} else {
+		var dir string
+		dir, err = removed.Dir()
+		paths = []string{dir}
+	}
+	if err != nil {
+		return true, err
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := safefile.RemoveAll(path); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func ValidateRegistryName(name string) error {
+	if !registryNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid registry name %q: use lowercase letters, digits, '.', '-' and '_'", name)
+	}
+	return nil
+}
+
+func QualifiedName(registry, name string) string {
+	if registry == "" {
+		return name
+	}
+	return registry + "/" + name
+}
+
+func LockKey(registry, name string) string {
+	if registry == "" || registry == DefaultRegistry {
+		return name
+	}
+	return QualifiedName(registry, name)
+}
+
+func SplitReference(source string) (string, string) {
+	registries, err := Registries()
+	if err != nil {
+		return "", source
+	}
+	return splitQualified(registries, source)
+}
+
+func RecordedSource(kind, name string) string {
+	record, ok, err := LoadLocalRecord(kind, name)
+	if err != nil || !ok {
+		return name
+	}
+	if _, configured, err := FindRegistry(record.Registry); err != nil || !configured {
+		return name
+	}
+	return QualifiedName(record.Registry, name)
+}
+
+func (r Registry) Dir() (string, error) {
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	if r.Name == DefaultRegistry {
+		return root, nil
+	}
+	return filepath.Join(root, registriesDir, r.Name), nil
+}
+
+func (r Registry) withDefaults() Registry {
+	if r.Name == DefaultRegistry {
+		if value := strings.TrimSpace(os.Getenv("MCP_REGISTRY_REPO")); value != "" {
+			r.URL = value
+		}
+		if value := strings.TrimSpace(os.Getenv("MCP_REGISTRY_BRANCH")); value != "" {
+			r.Branch = value
+		}
+	}
+	if r.Branch == "" {
+		r.Branch = defaultBranch
+	}
+	return r
+}
+
+func (r Registry) cachePaths() ([]string, error) {
+	dir, err := r.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		filepath.Join(dir, skillIndex),
+		filepath.Join(dir, mcpIndex),
+		filepath.Join(dir, metaFile),
+		filepath.Join(dir, cacheDir),
+	}, nil
+}
+
+func splitQualified(registries []Registry, source string) (string, string) {
+	prefix, name, ok := strings.Cut(source, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", source
+	}
+	for _, reg := range registries {
+		if reg.Name == prefix {
+			return prefix, name
+		}
+	}
+	return "", source
+}
+
+func registriesPath() (string, error) {
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, registriesFile), nil
+}
+
+func loadRegistries() ([]Registry, error) {
+	path, err := registriesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := safefile.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Registry{{Name: DefaultRegistry, URL: defaultRepo, Branch: defaultBranch}}, nil
+		}
+		return nil, err
+	}
+	var config registriesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for _, reg := range config.Registries {
+		if err := ValidateRegistryName(reg.Name); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		if seen[reg.Name] {
+			return nil, fmt.Errorf("invalid %s: registry %s is listed twice", path, reg.Name)
+		}
+		seen[reg.Name] = true
+	}
+	return config.Registries, nil
+}
+
+func saveRegistries(registries []Registry) error {
+	path, err := registriesPath()
+	if err != nil {
+		return err
+	}
+	if registries == nil {
+		registries = []Registry{}
+	}
+	data, err := json.MarshalIndent(registriesConfig{Registries: registries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,509 @@
+package registryindex
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"mcp-skill-manager/internal/credentials"
+	"mcp-skill-manager/internal/httpclient"
+)
+
+const (
+	SourceGitHub = "github"
+	SourceGitLab = "gitlab"
+	SourceGitea  = "gitea"
+	SourceHTTP   = "http"
+	SourceLocal  = "local"
+)
+
+var SourceTypes = []string{SourceGitHub, SourceGitLab, SourceGitea, SourceHTTP, SourceLocal}
+
+type RegistrySource interface {
+	Type() string
+	Location(file string) string
+	Fetch(file string, cached Validators) (FetchResult, error)
+	FetchSkill(name, dest string) error
+}
+
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+type FetchResult struct {
+	Data        []byte
+	Validators  Validators
+	NotModified bool
+}
+
+type NotFoundError struct {
+	Location string
+}
+
+func (e NotFoundError) Error() string {
+	return "not found: " + e.Location
+}
+
+func IsNotFound(err error) bool {
+	var notFound NotFoundError
+	return errors.As(err, &notFound)
+}
+
+func SourceFor(registry string) (RegistrySource, error) {
+	reg, err := registryNamed(registry)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Source()
+}
+
+func (r Registry) Source() (RegistrySource, error) {
+	kind, err := r.SourceType()
+	if err != nil {
+		return nil, err
+	}
+	location := strings.TrimSpace(r.URL)
+	if location == "" {
+		return nil, fmt.Errorf("registry repo is empty")
+	}
+	switch kind {
+	case SourceLocal:
+		root, err := localRoot(location)
+		if err != nil {
+			return nil, err
+		}
+		return localSource{root: root}, nil
+	case SourceHTTP:
+		parsed, err := parseHTTPURL(location)
+		if err != nil {
+			return nil, err
+		}
+		return httpSource{kind: kind, base: strings.TrimSuffix(parsed.String(), "/") + "/", registry: r.Name, tokenEnv: r.TokenEnv}, nil
+	case SourceGitHub:
+		repo := location
+		clone := ""
+		if host, path, ok := parseSSHURL(location); ok {
+			repo, clone = path, location
+			if host != "github.com" {
+				return nil, fmt.Errorf("invalid github registry repo: %s", location)
+			}
+		} else if parsed, err := url.Parse(location); err == nil && parsed.Host != "" {
+			repo = parsed.Path
+		}
+		repo = strings.Trim(strings.TrimSuffix(strings.Trim(repo, "/"), ".git"), "/")
+		if strings.Count(repo, "/") != 1 {
+			return nil, fmt.Errorf("invalid registry repo: %s", repo)
+		}
+		if clone == "" {
+			clone = "https://github.com/" + repo + ".git"
+		}
+		return httpSource{
+			kind:     kind,
+			base:     fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/", repo, r.Branch),
+			clone:    clone,
+			branch:   r.Branch,
+			registry: r.Name,
+			tokenEnv: r.TokenEnv,
+		}, nil
+	default:
+		scheme, host, project, clone := "https", "", "", ""
+		if sshHost, path, ok := parseSSHURL(location); ok {
+			host, project, clone = sshHost, path, location
+		} else {
+			parsed, err := parseHTTPURL(location)
+			if err != nil {
+				return nil, err
+			}
+			scheme, host, project = parsed.Scheme, parsed.Host, parsed.Path
+		}
+		project = strings.TrimSuffix(strings.Trim(project, "/"), ".git")
+		if strings.Count(project, "/") < 1 || (kind == SourceGitea && strings.Count(project, "/") != 1) {
+			return nil, fmt.Errorf("invalid %s registry repo: %s", kind, location)
+		}
+		repoURL := scheme + "://" + host + "/" + project
+		if clone == "" {
+			clone = repoURL + ".git"
+		}
+		base := repoURL + "/-/raw/" + r.Branch + "/"
+		if kind == SourceGitea {
+			base = repoURL + "/raw/branch/" + r.Branch + "/"
+		}
+		return httpSource{kind: kind, base: base, clone: clone, branch: r.Branch, registry: r.Name, tokenEnv: r.TokenEnv}, nil
+	}
+}
+
+func (r Registry) SourceType() (string, error) {
+	if kind := strings.ToLower(strings.TrimSpace(r.Type)); kind != "" {
+		for _, known := range SourceTypes {
+			if kind == known {
+				return kind, nil
+			}
+		}
+		return "", fmt.Errorf("unknown registry type %q (use %s)", r.Type, strings.Join(SourceTypes, ", "))
+	}
+	location := strings.TrimSpace(r.URL)
+	if isLocalLocation(location) {
+		return SourceLocal, nil
+	}
+	if host, _, ok := parseSSHURL(location); ok {
+		switch host {
+		case "github.com":
+			return SourceGitHub, nil
+		case "gitlab.com":
+			return SourceGitLab, nil
+		case "gitea.com", "codeberg.org":
+			return SourceGitea, nil
+		}
+		return "", fmt.Errorf("cannot detect the registry type of %s; set --type gitlab or --type gitea", location)
+	}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		parsed, err := parseHTTPURL(location)
+		if err != nil {
+			return "", err
+		}
+		switch strings.ToLower(parsed.Hostname()) {
+		case "github.com", "www.github.com":
+			return SourceGitHub, nil
+		case "gitlab.com":
+			return SourceGitLab, nil
+		case "gitea.com", "codeberg.org":
+			return SourceGitea, nil
+		}
+		return SourceHTTP, nil
+	}
+	if strings.Count(strings.TrimSuffix(location, ".git"), "/") == 1 {
+		return SourceGitHub, nil
+	}
+	return "", fmt.Errorf("unsupported registry URL: %s", location)
+}
+
+type httpSource struct {
+	kind     string
+	base     string
+	clone    string
+	branch   string
+	registry string
+	tokenEnv string
+}
+
+func (s httpSource) Type() string {
+	return s.kind
+}
+
+func (s httpSource) Location(file string) string {
+	return s.base + file
+}
+
+func (s httpSource) Fetch(file string, cached Validators) (FetchResult, error) {
+	resp, err := s.get(file, cached)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	defer resp.Body.Close()
+	validators := Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	if resp.StatusCode == http.StatusNotModified {
+		if validators == (Validators{}) {
+			validators = cached
+		}
+		return FetchResult{Validators: validators, NotModified: true}, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	return FetchResult{Data: data, Validators: validators}, nil
+}
+
+func (s httpSource) FetchSkill(name, dest string) error {
+	if Offline() {
+		return OfflineError{What: "skill " + name}
+	}
+	if s.clone != "" {
+		checkout := dest + ".checkout"
+		defer os.RemoveAll(checkout)
+		token, err := s.token(s.clone)
+		if err != nil {
+			return err
+		}
+		if err := gitCloneBranch(s.clone, s.branch, checkout, token); err != nil {
+			return err
+		}
+		path := filepath.Join(checkout, "skill", name)
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("skill path not found: skill/%s", name)
+		}
+		return os.Rename(path, dest)
+	}
+	archive := "skill/" + name + ".tar.gz"
+	resp, err := s.get(archive, Validators{})
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("skill archive not found: %s", s.Location(archive))
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if err := extractTarGz(resp.Body, dest); err != nil {
+		return fmt.Errorf("invalid skill archive %s: %w", s.Location(archive), err)
+	}
+	return nil
+}
+
+func (s httpSource) get(file string, cached Validators) (*http.Response, error) {
+	location := s.Location(file)
+	if Offline() {
+		return nil, OfflineError{What: location}
+	}
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	if err := s.authorize(req); err != nil {
+		return nil, err
+	}
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, NotFoundError{Location: location}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 8*1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry fetch failed: %s (%s)", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (s httpSource) authorize(req *http.Request) error {
+	token, err := s.token(req.URL.String())
+	if err != nil || token.Value == "" {
+		return err
+	}
+	switch s.kind {
+	case SourceGitLab:
+		req.Header.Set("Private-Token", token.Value)
+	case SourceGitea:
+		req.Header.Set("Authorization", "token "+token.Value)
+	default:
+		req.Header.Set("Authorization", "Bearer "+token.Value)
+	}
+	return nil
+}
+
+func (s httpSource) token(location string) (credentials.Token, error) {
+	if s.tokenEnv == "" {
+		return credentials.Lookup(location)
+	}
+	value := strings.TrimSpace(os.Getenv(s.tokenEnv))
+	if value == "" {
+		return credentials.Token{}, fmt.Errorf("registry %s needs a token: %s is not set", s.registry, s.tokenEnv)
+	}
+	return credentials.Token{Value: value}, nil
+}
+
+type localSource struct {
+	root string
+}
+
+func (s localSource) Type() string {
+	return SourceLocal
+}
+
+func (s localSource) Location(file string) string {
+	return filepath.Join(s.root, filepath.FromSlash(file))
+}
+
+func (s localSource) Fetch(file string, cached Validators) (FetchResult, error) {
+	info, err := os.Stat(s.Location(file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FetchResult{}, NotFoundError{Location: s.Location(file)}
+		}
+		return FetchResult{}, err
+	}
+	validators := Validators{ETag: fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())}
+	if cached.ETag == validators.ETag {
+		return FetchResult{Validators: validators, NotModified: true}, nil
+	}
+	data, err := os.ReadFile(s.Location(file))
+	if err != nil {
+		return FetchResult{}, err
+	}
+	return FetchResult{Data: data, Validators: validators}, nil
+}
+
+func (s localSource) FetchSkill(name, dest string) error {
+	src := s.Location("skill/" + name)
+	info, err := os.Stat(src)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("skill path not found: %s", src)
+	}
+	return copyTree(src, dest)
+}
+
+func isLocalLocation(location string) bool {
+	return strings.HasPrefix(location, "file://") ||
+		strings.HasPrefix(location, "./") ||
+		strings.HasPrefix(location, "../") ||
+		strings.HasPrefix(location, "~") ||
+		location == "." ||
+		filepath.IsAbs(location)
+}
+
+func localRoot(location string) (string, error) {
+	if strings.HasPrefix(location, "file://") {
+		parsed, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("invalid registry URL %s: %w", location, err)
+		}
+		if parsed.Host != "" && parsed.Host != "localhost" {
+			return "", fmt.Errorf("unsupported file URL host: %s", parsed.Host)
+		}
+		location = filepath.FromSlash(parsed.Path)
+	}
+	if location == "~" || strings.HasPrefix(location, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		location = filepath.Join(home, strings.TrimPrefix(location, "~"))
+	}
+	return filepath.Abs(location)
+}
+
+func parseHTTPURL(location string) (*url.URL, error) {
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL %s: %w", location, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid registry URL: %s", location)
+	}
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	return parsed, nil
+}
+
+func parseSSHURL(location string) (string, string, bool) {
+	if strings.HasPrefix(location, "ssh://") {
+		parsed, err := url.Parse(location)
+		if err != nil || parsed.Hostname() == "" {
+			return "", "", false
+		}
+		return strings.ToLower(parsed.Hostname()), strings.Trim(parsed.Path, "/"), true
+	}
+	userHost, path, ok := strings.Cut(location, ":")
+	if !ok || !strings.Contains(userHost, "@") || strings.Contains(userHost, "/") {
+		return "", "", false
+	}
+	host := userHost[strings.LastIndex(userHost, "@")+1:]
+	if host == "" || path == "" {
+		return "", "", false
+	}
+	return strings.ToLower(host), strings.Trim(path, "/"), true
+}
+
+func extractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == "." {
+			continue
+		}
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("entry outside the archive root: %s", header.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm()|0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, reader); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry type in archive: %s", header.Name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "SKILL.md")); err != nil {
+		return fmt.Errorf("SKILL.md missing at the archive root")
+	}
+	return nil
+}
+
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("symlink not supported: %s", path)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -3,78 +3,184 @@ package registryindex
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
-	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/safefile"
 )
 
 type Meta struct {
-	Repo      string `json:"repo"`
-	Branch    string `json:"branch"`
-	LastSync  string `json:"lastSync"`
-	SkillFile string `json:"skillIndex"`
-	MCPFile   string `json:"mcpIndex"`
+	Repo       string                `json:"repo"`
+	Type       string                `json:"type,omitempty"`
+	Branch     string                `json:"branch"`
+	LastSync   string                `json:"lastSync"`
+	SkillFile  string                `json:"skillIndex"`
+	MCPFile    string                `json:"mcpIndex"`
+	Validators map[string]Validators `json:"validators,omitempty"`
 }
 
 func SyncIfStale() error {
-	root, err := installer.LocalStoreRoot()
+	registries, err := Registries()
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(root, 0o755); err != nil {
+	for _, reg := range registries {
+		dir, err := reg.Dir()
+		if err != nil {
+			return err
+		}
+		meta, _ := loadMeta(dir)
+		if reg.offlineSkipped() || !shouldSync(meta, dir, reg) {
+			continue
+		}
+		if err := reg.sync(dir); err != nil {
+			return registryError(registries, reg, err)
+		}
+	}
+	if Offline() {
+		return ensureCachedIndexes(registries)
+	}
+	return nil
+}
+
+func Refresh() error {
+	registries, err := Registries()
+	if err != nil {
 		return err
 	}
-
-	meta, _ := loadMeta(root)
-	if !shouldSync(meta, root) {
-		return nil
+	for _, reg := range registries {
+		if err := reg.Refresh(); err != nil {
+			return registryError(registries, reg, err)
+		}
 	}
+	return nil
+}
 
-	base, err := rawBaseURL()
+func (r Registry) Refresh() error {
+	dir, err := r.Dir()
 	if err != nil {
 		return err
 	}
+	return r.sync(dir)
+}
 
-	skillURL := base + skillIndex
-	mcpURL := base + mcpIndex
+func (r Registry) LastSync() (time.Time, bool, error) {
+	dir, err := r.Dir()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	meta, err := loadMeta(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	if meta.LastSync == "" {
+		return time.Time{}, false, nil
+	}
+	lastSync, err := time.Parse(time.RFC3339, meta.LastSync)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid lastSync in %s: %w", metaFile, err)
+	}
+	return lastSync, true, nil
+}
 
-	if err := downloadFile(skillURL, filepath.Join(root, skillIndex)); err != nil {
+func (r Registry) sync(dir string) error {
+	if r.offlineSkipped() {
+		return OfflineError{What: "registry " + r.Name}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
-	if err := downloadFile(mcpURL, filepath.Join(root, mcpIndex)); err != nil {
+	source, err := r.Source()
+	if err != nil {
 		return err
 	}
+	previous, _ := loadMeta(dir)
+	sameSource := previous.Repo == r.URL && previous.Branch == r.Branch && previous.Type == source.Type()
+	validators := map[string]Validators{}
+	for _, name := range []string{skillIndex, mcpIndex} {
+		path := filepath.Join(dir, name)
+		var cached Validators
+		if sameSource && fileExists(path) {
+			cached = previous.Validators[name]
+		}
+		result, err := source.Fetch(name, cached)
+		if err != nil {
+			if IsNotFound(err) {
+				return fmt.Errorf("registry fetch failed: %s not found", source.Location(name))
+			}
+			return err
+		}
+		validators[name] = result.Validators
+		if result.NotModified {
+			continue
+		}
+		if err := safefile.WriteFile(path, result.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	synced[r.Name] = true
+
+	meta := Meta{
+		Repo:       r.URL,
+		Type:       source.Type(),
+		Branch:     r.Branch,
+		LastSync:   time.Now().UTC().Format(time.RFC3339),
+		SkillFile:  skillIndex,
+		MCPFile:    mcpIndex,
+		Validators: validators,
+	}
+	return saveMeta(dir, meta)
+}
 
-	meta = Meta{
-		Repo:      registryRepo(),
-		Branch:    registryBranch(),
-		LastSync:  time.Now().UTC().Format(time.RFC3339),
-		SkillFile: skillIndex,
-		MCPFile:   mcpIndex,
-	}
-	return saveMeta(root, meta)
+func registryError(registries []Registry, reg Registry, err error) error {
+	if len(registries) > 1 {
+		return fmt.Errorf("%s: %w", reg.Name, err)
+	}
+	return err
 }
 
-func shouldSync(meta Meta, root string) bool {
-	if meta.LastSync == "" {
+func CachePaths() ([]string, error) {
+	registries, err := Registries()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, reg := range registries {
+		regPaths, err := reg.cachePaths()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, regPaths...)
+	}
+	return paths, nil
+}
+
+func shouldSync(meta Meta, dir string, reg Registry) bool {
+	if meta.LastSync == "" || (refresh && !synced[reg.Name]) {
+		return true
+	}
+	if meta.Repo != reg.URL || meta.Branch != reg.Branch {
+		return true
+	}
+	if kind, err := reg.SourceType(); err != nil || kind == SourceLocal || (meta.Type != "" && meta.Type != kind) {
 		return true
 	}
-	if !fileExists(filepath.Join(root, skillIndex)) || !fileExists(filepath.Join(root, mcpIndex)) {
+	if !fileExists(filepath.Join(dir, skillIndex)) || !fileExists(filepath.Join(dir, mcpIndex)) {
 		return true
 	}
 	lastSync, err := time.Parse(time.RFC3339, meta.LastSync)
 	if err != nil {
 		return true
 	}
-	return time.Since(lastSync) > syncTTL
+	return time.Since(lastSync) > registryTTL()
 }
 
-func loadMeta(root string) (Meta, error) {
-	path := filepath.Join(root, metaFile)
+func loadMeta(dir string) (Meta, error) {
+	path := filepath.Join(dir, metaFile)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return Meta{}, err
@@ -86,43 +192,13 @@ func loadMeta(root string) (Meta, error) {
 	return meta, nil
 }
 
-func saveMeta(root string, meta Meta) error {
-	path := filepath.Join(root, metaFile)
+func saveMeta(dir string, meta Meta) error {
+	path := filepath.Join(dir, metaFile)
 	data, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
-}
-
-func downloadFile(url, dest string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 8*1024))
-		return fmt.Errorf("registry fetch failed: %s (%s)", resp.Status, string(body))
-	}
-
-	tmp, err := os.CreateTemp(filepath.Dir(dest), "index-*")
-	if err != nil {
-		return err
-	}
-	defer func() {
-		tmp.Close()
-		os.Remove(tmp.Name())
-	}()
-
-	if _, err := io.Copy(tmp, resp.Body); err != nil {
-		return err
-	}
-	if err := tmp.Close(); err != nil {
-		return err
-	}
-	return os.Rename(tmp.Name(), dest)
+	return safefile.WriteFile(path, data, 0o644)
 }
 
 func fileExists(path string) bool {
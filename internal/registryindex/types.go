@@ -1,13 +1,15 @@
 package registryindex
 
 type SkillEntry struct {
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	Repo        string `json:"repo"`
-	Head        string `json:"head"`
-	UpdatedAt   string `json:"updatedAt"`
-	Version     string `json:"version,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	Repo        string   `json:"repo"`
+	Head        string   `json:"head"`
+	UpdatedAt   string   `json:"updatedAt"`
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Requires    []string `json:"requires,omitempty"`
+	Registry    string   `json:"registry,omitempty"`
 }
 
 type SkillIndex struct {
@@ -30,6 +32,7 @@ type MCPEntry struct {
 	Head        string            `json:"head,omitempty"`
 	UpdatedAt   string            `json:"updatedAt,omitempty"`
 	CheckedAt   string            `json:"checkedAt,omitempty"`
+	Registry    string            `json:"registry,omitempty"`
 }
 
 type MCPRun struct {
@@ -45,6 +48,8 @@ type MCPInput struct {
 	Required bool     `json:"required,omitempty"`
 	Default  string   `json:"default,omitempty"`
 	Options  []string `json:"options,omitempty"`
+	Secret   bool     `json:"secret,omitempty"`
+	Env      string   `json:"env,omitempty"`
 }
 
 type MCPIndex struct {
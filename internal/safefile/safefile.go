@@ -0,0 +1,150 @@
+package safefile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
+)
+
+const (
+	lockTimeout  = 30 * time.Second
+	lockInterval = 50 * time.Millisecond
+)
+
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if plan.Intercepts(path) {
+		return plan.RecordWrite(path, data)
+	}
+	target := resolveTarget(path)
+	if info, err := os.Stat(target); err == nil {
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		tmp.Close()
+		os.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, target); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+func ReadFile(path string) ([]byte, error) {
+	if data, ok, err := plan.Staged(path); ok {
+		return data, err
+	}
+	return os.ReadFile(path)
+}
+
+func RemoveAll(path string) error {
+	if plan.Intercepts(path) {
+		return plan.RecordRemove(path)
+	}
+	return os.RemoveAll(path)
+}
+
+func Lock(path string) (func(), error) {
+	if plan.Active() {
+		return func() {}, nil
+	}
+	lockPath, err := lockPathFor(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if locked {
+			return func() {
+				unlock(file)
+				file.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("timed out waiting for lock on %s", path)
+		}
+		time.Sleep(lockInterval)
+	}
+}
+
+func WithLock(path string, fn func() error) error {
+	release, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+func resolveTarget(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+func lockPathFor(path string) (string, error) {
+	abs, err := filepath.Abs(resolveTarget(path))
+	if err != nil {
+		return "", err
+	}
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, "locks")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".lock"), nil
+}
+
+func syncDir(dir string) {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	handle.Sync()
+	handle.Close()
+}
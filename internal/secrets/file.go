@@ -0,0 +1,278 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/safefile"
+)
+
+const (
+	vaultVersion    = 1
+	vaultKDF        = "pbkdf2-sha256"
+	vaultIterations = 600000
+	vaultKeySize    = 32
+	vaultSaltSize   = 16
+)
+
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Data       string `json:"data"`
+}
+
+type fileStore struct {
+	path       string
+	passphrase func(create bool) (string, error)
+
+	loaded     bool
+	values     map[string]string
+	salt       []byte
+	iterations int
+	key        []byte
+}
+
+func newFileStore(dir string, opts Options) *fileStore {
+	return &fileStore{
+		path:       filepath.Join(dir, "vault.json"),
+		passphrase: opts.Passphrase,
+	}
+}
+
+func (s *fileStore) Backend() string {
+	return BackendFile
+}
+
+func (s *fileStore) Location() string {
+	return s.path
+}
+
+func (s *fileStore) Get(name string) (string, bool, error) {
+	if err := s.load(); err != nil {
+		return "", false, err
+	}
+	value, ok := s.values[name]
+	return value, ok, nil
+}
+
+func (s *fileStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return safefile.WithLock(s.path, func() error {
+		s.loaded = false
+		if err := s.load(); err != nil {
+			return err
+		}
+		s.values[name] = value
+		return s.save()
+	})
+}
+
+func (s *fileStore) Delete(name string) (bool, error) {
+	removed := false
+	err := safefile.WithLock(s.path, func() error {
+		s.loaded = false
+		if err := s.load(); err != nil {
+			return err
+		}
+		if _, ok := s.values[name]; !ok {
+			return nil
+		}
+		delete(s.values, name)
+		removed = true
+		return s.save()
+	})
+	return removed, err
+}
+
+func (s *fileStore) Names() ([]string, error) {
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(s.values))
+	for name := range s.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *fileStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	data, err := safefile.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.values = map[string]string{}
+			s.loaded = true
+			return nil
+		}
+		return err
+	}
+	var vault vaultFile
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+	if vault.Version != vaultVersion || vault.KDF != vaultKDF || vault.Iterations <= 0 {
+		return fmt.Errorf("%s: unsupported vault format (version %d, kdf %q)", s.path, vault.Version, vault.KDF)
+	}
+	salt, err := base64.StdEncoding.DecodeString(vault.Salt)
+	if err != nil {
+		return fmt.Errorf("%s: invalid salt: %w", s.path, err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(vault.Nonce)
+	if err != nil {
+		return fmt.Errorf("%s: invalid nonce: %w", s.path, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(vault.Data)
+	if err != nil {
+		return fmt.Errorf("%s: invalid data: %w", s.path, err)
+	}
+
+	if s.key == nil || !bytes.Equal(s.salt, salt) || s.iterations != vault.Iterations {
+		passphrase, err := s.readPassphrase(false)
+		if err != nil {
+			return err
+		}
+		s.key = pbkdf2Key([]byte(passphrase), salt, vault.Iterations, vaultKeySize)
+		s.salt = salt
+		s.iterations = vault.Iterations
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return fmt.Errorf("%s: invalid nonce length", s.path)
+	}
+	plain, err := gcm.Open(nil, nonce, sealed, vaultAdditionalData())
+	if err != nil {
+		s.key = nil
+		return fmt.Errorf("cannot unlock %s: wrong passphrase or corrupted vault", s.path)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+	s.values = values
+	s.loaded = true
+	return nil
+}
+
+func (s *fileStore) save() error {
+	if s.key == nil {
+		passphrase, err := s.readPassphrase(true)
+		if err != nil {
+			return err
+		}
+		salt := make([]byte, vaultSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		s.key = pbkdf2Key([]byte(passphrase), salt, vaultIterations, vaultKeySize)
+		s.salt = salt
+		s.iterations = vaultIterations
+	}
+	plain, err := json.Marshal(s.values)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(vaultFile{
+		Version:    vaultVersion,
+		KDF:        vaultKDF,
+		Iterations: s.iterations,
+		Salt:       base64.StdEncoding.EncodeToString(s.salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Data:       base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plain, vaultAdditionalData())),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !plan.Active() {
+		if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+			return err
+		}
+	}
+	return safefile.WriteFile(s.path, append(data, '\n'), 0o600)
+}
+
+func (s *fileStore) readPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if s.passphrase == nil {
+		return "", fmt.Errorf("the secrets vault is locked: set %s", PassphraseEnv)
+	}
+	passphrase, err := s.passphrase(create)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("an empty passphrase cannot unlock the secrets vault")
+	}
+	return passphrase, nil
+}
+
+func vaultAdditionalData() []byte {
+	return []byte(fmt.Sprintf("mcp-skill-vault/%d", vaultVersion))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func pbkdf2Key(password, salt []byte, iterations, length int) []byte {
+	prf := hmac.New(sha256.New, password)
+	size := prf.Size()
+	blocks := (length + size - 1) / size
+	key := make([]byte, 0, blocks*size)
+	var counter [4]byte
+	u := make([]byte, 0, size)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for n := 1; n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:length]
+}
@@ -0,0 +1,185 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func openFileStore(t *testing.T, dir, passphrase string) *fileStore {
+	t.Helper()
+	t.Setenv(PassphraseEnv, "")
+	return newFileStore(dir, Options{Passphrase: func(bool) (string, error) { return passphrase, nil }})
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := openFileStore(t, dir, "correct horse")
+	if err := store.Set("API_TOKEN", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("OTHER", "value with spaces"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	reopened := openFileStore(t, dir, "correct horse")
+	if got, ok, err := reopened.Get("API_TOKEN"); err != nil || !ok || got != "s3cret" {
+		t.Fatalf("Get(API_TOKEN) = %q, %v, %v", got, ok, err)
+	}
+	if _, ok, err := reopened.Get("MISSING"); err != nil || ok {
+		t.Fatalf("Get(MISSING) = ok %v, err %v", ok, err)
+	}
+	if removed, err := reopened.Delete("OTHER"); err != nil || !removed {
+		t.Fatalf("Delete(OTHER) = %v, %v", removed, err)
+	}
+
+	names, err := openFileStore(t, dir, "correct horse").Names()
+	if err != nil {
+		t.Fatalf("Names: %v", err)
+	}
+	if want := []string{"API_TOKEN"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Names = %v, want %v", names, want)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	if err := openFileStore(t, dir, "correct horse").Set("API_TOKEN", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	value, ok, err := openFileStore(t, dir, "battery staple").Get("API_TOKEN")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Fatalf("Get err = %v, want a wrong passphrase error", err)
+	}
+	if ok || value != "" {
+		t.Fatalf("Get returned %q, %v with a wrong passphrase", value, ok)
+	}
+}
+
+func TestFileStoreLockedWithoutPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	if err := openFileStore(t, dir, "correct horse").Set("API_TOKEN", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, _, err := newFileStore(dir, Options{}).Get("API_TOKEN"); err == nil || !strings.Contains(err.Error(), PassphraseEnv) {
+		t.Fatalf("Get err = %v, want a locked vault error naming %s", err, PassphraseEnv)
+	}
+	t.Setenv(PassphraseEnv, "correct horse")
+	if got, ok, err := newFileStore(dir, Options{}).Get("API_TOKEN"); err != nil || !ok || got != "s3cret" {
+		t.Fatalf("Get with %s = %q, %v, %v", PassphraseEnv, got, ok, err)
+	}
+}
+
+func TestFileStoreCorruptedVault(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(vault *vaultFile)
+		want    string
+	}{
+		{
+			name: "flipped ciphertext byte",
+			corrupt: func(vault *vaultFile) {
+				data, _ := base64.StdEncoding.DecodeString(vault.Data)
+				data[0] ^= 0xff
+				vault.Data = base64.StdEncoding.EncodeToString(data)
+			},
+			want: "wrong passphrase or corrupted vault",
+		},
+		{
+			name:    "truncated nonce",
+			corrupt: func(vault *vaultFile) { vault.Nonce = base64.StdEncoding.EncodeToString([]byte("short")) },
+			want:    "invalid nonce length",
+		},
+		{
+			name:    "invalid salt",
+			corrupt: func(vault *vaultFile) { vault.Salt = "not base64!" },
+			want:    "invalid salt",
+		},
+		{
+			name:    "unsupported version",
+			corrupt: func(vault *vaultFile) { vault.Version = 99 },
+			want:    "unsupported vault format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := openFileStore(t, dir, "correct horse")
+			if err := store.Set("API_TOKEN", "s3cret"); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			data, err := os.ReadFile(store.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var vault vaultFile
+			if err := json.Unmarshal(data, &vault); err != nil {
+				t.Fatal(err)
+			}
+			tt.corrupt(&vault)
+			if data, err = json.Marshal(vault); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(store.path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := openFileStore(t, dir, "correct horse").Get("API_TOKEN"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Get err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("not json", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openFileStore(t, dir, "correct horse")
+		if err := os.WriteFile(store.path, []byte("{\"version\": 1,"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := store.Get("API_TOKEN"); err == nil {
+			t.Fatal("Get succeeded on a truncated vault")
+		}
+	})
+}
+
+func TestFileStorePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	store := openFileStore(t, t.TempDir(), "correct horse")
+	if err := store.Set("API_TOKEN", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	info, err := os.Stat(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Fatalf("vault mode = %o, want 600", mode)
+	}
+}
+
+func TestPBKDF2Key(t *testing.T) {
+	tests := []struct {
+		password   string
+		salt       string
+		iterations int
+		length     int
+		want       string
+	}{
+		{"password", "salt", 1, 32, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{"password", "salt", 2, 32, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{"password", "salt", 4096, 32, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 40, "348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1c635518c7dac47e9"},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(pbkdf2Key([]byte(tt.password), []byte(tt.salt), tt.iterations, tt.length))
+		if got != tt.want {
+			t.Errorf("pbkdf2Key(%q, %q, %d, %d) = %s, want %s", tt.password, tt.salt, tt.iterations, tt.length, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/safefile"
+)
+
+type keyringStore struct {
+	index string
+}
+
+func newKeyringStore(dir string) *keyringStore {
+	return &keyringStore{index: filepath.Join(dir, "keyring.json")}
+}
+
+func keyringAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "windows":
+		return false
+	default:
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	}
+}
+
+func (s *keyringStore) Backend() string {
+	return BackendKeyring
+}
+
+func (s *keyringStore) Location() string {
+	if runtime.GOOS == "darwin" {
+		return "macOS keychain (service " + keyringService + ")"
+	}
+	return "Secret Service (service " + keyringService + ")"
+}
+
+func (s *keyringStore) Get(name string) (string, bool, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", name, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", name)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (strings.TrimSpace(stderr.String()) == "" || isKeychainNotFound(stderr.String())) {
+			return "", false, nil
+		}
+		return "", false, keyringError("read", name, err, stderr.String())
+	}
+	value := stdout.String()
+	if runtime.GOOS == "darwin" {
+		value = strings.TrimSuffix(value, "\n")
+	}
+	return value, true, nil
+}
+
+func (s *keyringStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		if plan.Active() {
+			plan.RecordCommand("", "security add-generic-password -U -s "+keyringService+" -a "+name+" -w <secret>")
+			return nil
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("cannot store %s in the macOS keychain: the value contains a line break (use %s=%s)", name, BackendEnv, BackendFile)
+		}
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", name, "-w")
+		cmd.Stdin = strings.NewReader(value + "\n" + value + "\n")
+		detachTerminal(cmd)
+	} else {
+		if plan.Active() {
+			plan.RecordCommand("", "secret-tool store --label '"+keyringService+" "+name+"' service "+keyringService+" account "+name)
+			return nil
+		}
+		cmd = exec.Command("secret-tool", "store", "--label", keyringService+" "+name, "service", keyringService, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return keyringError("store", name, err, stderr.String())
+	}
+	return s.updateIndex(name, true)
+}
+
+func (s *keyringStore) Delete(name string) (bool, error) {
+	if _, ok, err := s.Get(name); err != nil || !ok {
+		if err == nil {
+			err = s.updateIndex(name, false)
+		}
+		return false, err
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", name)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "account", name)
+	}
+	if plan.Active() {
+		plan.RecordCommand("", strings.Join(cmd.Args, " "))
+		return true, nil
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return false, keyringError("remove", name, err, stderr.String())
+	}
+	return true, s.updateIndex(name, false)
+}
+
+func (s *keyringStore) Names() ([]string, error) {
+	data, err := safefile.ReadFile(s.index)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.index, err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *keyringStore) updateIndex(name string, present bool) error {
+	return safefile.WithLock(s.index, func() error {
+		names, err := s.Names()
+		if err != nil {
+			return err
+		}
+		kept := make([]string, 0, len(names)+1)
+		for _, existing := range names {
+			if existing != name {
+				kept = append(kept, existing)
+			}
+		}
+		if present {
+			kept = append(kept, name)
+		}
+		sort.Strings(kept)
+		data, err := json.MarshalIndent(kept, "", "  ")
+		if err != nil {
+			return err
+		}
+		return safefile.WriteFile(s.index, append(data, '\n'), 0o600)
+	})
+}
+
+func isKeychainNotFound(stderr string) bool {
+	return strings.Contains(stderr, "could not be found in the keychain")
+}
+
+func keyringError(action, name string, err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("keyring %s %s failed: %s", action, name, msg)
+	}
+	return fmt.Errorf("keyring %s %s failed: %v", action, name, err)
+}
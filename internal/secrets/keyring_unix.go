@@ -0,0 +1,12 @@
+//go:build unix
+
+package secrets
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func detachTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,7 @@
+//go:build windows
+
+package secrets
+
+import "os/exec"
+
+func detachTerminal(cmd *exec.Cmd) {}
@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mcp-skill-manager/internal/installer"
+)
+
+const (
+	BackendFile    = "file"
+	BackendKeyring = "keyring"
+
+	BackendEnv    = "MCP_SKILL_SECRETS_BACKEND"
+	PassphraseEnv = "MCP_SKILL_VAULT_PASSPHRASE"
+
+	keyringService = "mcp-skill-manager"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Store interface {
+	Backend() string
+	Location() string
+	Get(name string) (string, bool, error)
+	Set(name, value string) error
+	Delete(name string) (bool, error)
+	Names() ([]string, error)
+}
+
+type Options struct {
+	Passphrase func(create bool) (string, error)
+}
+
+func Open(opts Options) (Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(BackendEnv)))
+	switch backend {
+	case "", "auto":
+		if keyringAvailable() {
+			return newKeyringStore(dir), nil
+		}
+		return newFileStore(dir, opts), nil
+	case BackendKeyring:
+		if !keyringAvailable() {
+			return nil, fmt.Errorf("no OS keyring available (needs security on macOS or secret-tool with a D-Bus session on Linux)")
+		}
+		return newKeyringStore(dir), nil
+	case BackendFile:
+		return newFileStore(dir, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid %s: %s (expected keyring or file)", BackendEnv, backend)
+	}
+}
+
+func Dir() (string, error) {
+	root, err := installer.LocalStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "secrets"), nil
+}
+
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits and underscores, not starting with a digit", name)
+	}
+	return nil
+}
+
+func EnvName(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		for _, r := range strings.ToUpper(part) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('_')
+			}
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
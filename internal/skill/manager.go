@@ -6,18 +6,29 @@ import (
 	"strings"
 
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/lockfile"
 	"mcp-skill-manager/internal/registryindex"
 )
 
 type Installed struct {
-	Name   string
-	Client installer.Tool
-	Scope  string
-	Path   string
+	Name   string         `json:"name"`
+	Client installer.Tool `json:"client"`
+	Scope  string         `json:"scope"`
+	Path   string         `json:"path"`
 }
 
-func Install(source, scope, cwd string, clients []installer.Tool, force bool) ([]Installed, error) {
-	if isLocalPath(source) || isRepoInput(source) {
+type InstallOptions struct {
+	Force  bool
+	Frozen bool
+}
+
+func Install(source, scope, cwd string, clients []installer.Tool, opts InstallOptions) ([]Installed, error) {
+	force := opts.Force
+	registry, name := registryindex.SplitReference(source)
+	if isLocalPath(source) || (registry == "" && isRepoInput(source)) {
+		if !isLocalPath(source) && registryindex.Offline() {
+			return nil, registryindex.OfflineError{What: "repository " + source}
+		}
 		records, err := installer.InstallFromInput(source, scope, clients, cwd, force)
 		if err != nil {
 			return nil, err
@@ -26,7 +37,10 @@ func Install(source, scope, cwd string, clients []installer.Tool, force bool) ([
 	}
 
 	if err := registryindex.EnsureIndexes(); err != nil {
-		records, localErr := installer.InstallFromLocalStore(source, scope, clients, cwd, force)
+		if opts.Frozen {
+			return nil, err
+		}
+		records, localErr := installFromStore(registry, name, scope, cwd, clients, force)
 		if localErr != nil {
 			return nil, err
 		}
@@ -37,27 +51,96 @@ func Install(source, scope, cwd string, clients []installer.Tool, force bool) ([
 		return nil, err
 	}
 	if ok {
-		if err := registryindex.SyncSkill(entry); err != nil {
-			records, localErr := installer.InstallFromLocalStore(entry.Name, scope, clients, cwd, force)
-			if localErr != nil {
-				return nil, err
-			}
-			return mapInstallRecords(records, scope), nil
-		}
-		records, err := installer.InstallFromLocalStore(entry.Name, scope, clients, cwd, force)
+		return installRegistrySkill(entry, scope, cwd, clients, opts)
+	}
+	if opts.Frozen {
+		return nil, fmt.Errorf("skill not found in registry: %s", source)
+	}
+
+	records, err := installFromStore(registry, name, scope, cwd, clients, force)
+	if err != nil {
+		return nil, fmt.Errorf("skill not found in registry or local store: %s", source)
+	}
+	return mapInstallRecords(records, scope), nil
+}
+
+func installRegistrySkill(entry registryindex.SkillEntry, scope, cwd string, clients []installer.Tool, opts InstallOptions) ([]Installed, error) {
+	lockPath, err := lockfile.Path(cwd)
+	if err != nil {
+		return nil, err
+	}
+	if registryindex.Offline() {
+		entry = registryindex.CachedSkillEntry(entry)
+	}
+	lockKey := registryindex.LockKey(entry.Registry, entry.Name)
+	var locked lockfile.Entry
+	if opts.Frozen {
+		locked, err = lockfile.CheckFrozen(lockPath, "skill", lockKey, entry.Head)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	staged, err := registryindex.StageSkill(entry)
+	if err != nil {
+		if opts.Frozen {
+			return nil, err
+		}
+		cachedPath, pathErr := registryindex.SkillPathInStore(entry.Registry, entry.Name)
+		if pathErr != nil {
+			return nil, err
+		}
+		records, localErr := installer.InstallFromDir(cachedPath, scope, clients, cwd, opts.Force)
+		if localErr != nil {
+			return nil, err
+		}
 		return mapInstallRecords(records, scope), nil
 	}
+	defer staged.Cleanup()
 
-	records, err := installer.InstallFromLocalStore(source, scope, clients, cwd, force)
+	hash, err := lockfile.HashDir(staged.Path)
 	if err != nil {
-		return nil, fmt.Errorf("skill not found in registry or local store: %s", source)
+		return nil, err
+	}
+	if opts.Frozen && locked.Hash != hash {
+		return nil, fmt.Errorf("skill %s content hash %s differs from lockfile hash %s", lockKey, hash, locked.Hash)
+	}
+	if err := staged.Commit(); err != nil {
+		return nil, err
+	}
+
+	records, err := installer.InstallFromDir(staged.Path, scope, clients, cwd, opts.Force)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockfile.Update(lockPath, "skill", lockKey, lockfile.Entry{
+		Repo:      entry.Repo,
+		Path:      entry.Path,
+		Head:      entry.Head,
+		UpdatedAt: entry.UpdatedAt,
+		Hash:      hash,
+	}); err != nil {
+		return nil, err
 	}
 	return mapInstallRecords(records, scope), nil
 }
 
+func installFromStore(registry, name, scope, cwd string, clients []installer.Tool, force bool) ([]installer.InstallRecord, error) {
+	if registry == "" {
+		if record, ok, err := registryindex.LoadLocalRecord("skill", name); err == nil && ok {
+			registry = record.Registry
+		}
+	}
+	if registry == "" {
+		return installer.InstallFromLocalStore(name, scope, clients, cwd, force)
+	}
+	path, err := registryindex.SkillPathInStore(registry, name)
+	if err != nil {
+		return nil, err
+	}
+	return installer.InstallFromDir(path, scope, clients, cwd, force)
+}
+
 func List(scopes []string, cwd string, clients []installer.Tool) ([]Installed, error) {
 	items, err := installer.ListInstalled(clients, scopes, cwd)
 	if err != nil {
@@ -109,20 +192,12 @@ func UninstallAll(scope, cwd string, clients []installer.Tool) ([]Installed, err
 	return results, nil
 }
 
-func LocalStorePaths() (string, string, error) {
-	skillRoot, err := installer.LocalSkillStore()
-	if err != nil {
-		return "", "", err
-	}
-	mcpRoot, err := installer.LocalMcpStore()
-	if err != nil {
-		return "", "", err
-	}
-	return skillRoot, mcpRoot, nil
+func LocalStorePaths() ([]string, error) {
+	return registryindex.StoreRoots()
 }
 
-func CleanLocalStore() error {
-	return installer.CleanLocalStore()
+func CleanLocalStore(roots []string) error {
+	return installer.ClearStores(roots)
 }
 
 func mapInstallRecords(records []installer.InstallRecord, scope string) []Installed {
@@ -151,7 +226,7 @@ func isRepoInput(value string) bool {
 	if value == "" {
 		return false
 	}
-	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "git@") {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "git@") || strings.HasPrefix(value, "ssh://") {
 		return true
 	}
 	return strings.Count(value, "/") == 1
@@ -39,6 +39,10 @@ func (a *App) Run(args []string) int {
 		return a.runUninstall(args[1:])
 	case "clean":
 		return a.runClean(args[1:])
+	case "sync":
+		return a.runSync(args[1:])
+	case "doctor":
+		return a.runDoctor(args[1:])
 	default:
 		fmt.Fprintf(a.errOut, "unknown command: %s\n", args[0])
 		a.printHelp()
@@ -56,6 +60,8 @@ Commands:
   update|upgrade      Update installed skills from registry
   uninstall|remove|rm <name>   Remove an installed skill
   clean              Clear local store (~/.mcp-skill/skill and ~/.mcp-skill/mcp)
+  sync               Reconcile installed skills with the project manifest (.mcp-skill.json)
+  doctor             Check installed skills, the local cache and the registry index
 
 Use "%s <command> -h" for command help.
 `, a.binaryName, a.binaryName)
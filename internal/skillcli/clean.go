@@ -3,14 +3,17 @@ package skillcli
 import (
 	"flag"
 	"fmt"
+	"strings"
 
 	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/skill"
 )
 
 func (a *App) runClean(args []string) int {
 	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
 	fs.SetOutput(a.errOut)
+	dryRun := fs.Bool("dry-run", false, "list what would be deleted without deleting it")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -23,31 +26,40 @@ func (a *App) runClean(args []string) int {
 		return 0
 	}
 
-	skillRoot, mcpRoot, err := skill.LocalStorePaths()
+	roots, err := skill.LocalStorePaths()
 	if err != nil {
 		fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
 		return 1
 	}
-
-	skillCount, err := countEntries(skillRoot)
-	if err != nil {
-		fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
-		return 1
+	var summary strings.Builder
+	for _, root := range roots {
+		count, err := countEntries(root)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(&summary, "- %s (%d item(s))\n", root, count)
 	}
-	mcpCount, err := countEntries(mcpRoot)
-	if err != nil {
-		fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
-		return 1
+
+	if *dryRun {
+		plan.Start()
+		defer plan.Stop()
+		if err := skill.CleanLocalStore(roots); err != nil {
+			fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
+			return 1
+		}
+		cli.PrintPlan(a.out, plan.Stop())
+		return 0
 	}
 
-	fmt.Fprintf(a.out, "Local store will be cleared:\n- %s (%d item(s))\n- %s (%d item(s))\n", skillRoot, skillCount, mcpRoot, mcpCount)
+	fmt.Fprintf(a.out, "Local store will be cleared:\n%s", summary.String())
 	if !confirmPrompt(a.out, "Type 'yes' to continue: ") {
 		fmt.Fprintln(a.out, "canceled")
 		return 0
 	}
 
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
-		return skill.CleanLocalStore()
+		return skill.CleanLocalStore(roots)
 	})
 	if err != nil {
 		fmt.Fprintf(a.errOut, "clean failed: %v\n", err)
@@ -58,10 +70,12 @@ func (a *App) runClean(args []string) int {
 }
 
 func (a *App) printCleanHelp() {
-	fmt.Fprintf(a.out, `Usage: %s clean
+	fmt.Fprintf(a.out, `Usage: %s clean [--dry-run]
 
 Clears:
   ~/.mcp-skill/skill
   ~/.mcp-skill/mcp
+  ~/.mcp-skill/registries/<name>/skill
+  ~/.mcp-skill/registries/<name>/mcp
 `, a.binaryName)
 }
@@ -0,0 +1,194 @@
+package skillcli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"mcp-skill-manager/internal/doctor"
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/safefile"
+	"mcp-skill-manager/internal/skill"
+)
+
+func (a *App) runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	fixFlag := fs.Bool("fix", false, "apply safe fixes")
+	clientFlag := fs.String("client", "", "comma-separated clients to check")
+	clientShort := fs.String("c", "", "alias for --client")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printDoctorHelp()
+		return 0
+	}
+	if len(positionals) > 0 {
+		fmt.Fprintln(a.errOut, "doctor does not accept arguments")
+		return 2
+	}
+
+	clientValue, err := resolveListClientValue(*clientFlag, *clientShort, "")
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client selection: %v\n", err)
+		return 2
+	}
+	tools, err := installer.ParseTools(clientValue)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid client list: %v\n", err)
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	report := &doctor.Report{}
+	items := a.checkInstalledSkills(report, tools, cwd)
+	checkSkillRequires(report, items)
+	checkSkillStore(report)
+	doctor.CheckLocalRecords(report, "skill")
+	doctor.CheckIndex(report, "skill")
+	return report.Finish(a.out, *fixFlag)
+}
+
+func (a *App) checkInstalledSkills(report *doctor.Report, tools []installer.Tool, cwd string) []skill.Installed {
+	items, err := skill.List([]string{installer.ScopeUser, installer.ScopeProject}, cwd, tools)
+	if err != nil {
+		report.Add(doctor.Finding{Severity: doctor.SeverityError, Subject: "installed skills", Problem: err.Error()})
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.Path] {
+			continue
+		}
+		seen[item.Path] = true
+		if hasSkillFile(item.Path) {
+			report.Pass()
+			continue
+		}
+		scopeFlag := "-l"
+		if item.Scope == installer.ScopeUser {
+			scopeFlag = "-g"
+		}
+		report.Add(doctor.Finding{
+			Severity: doctor.SeverityWarning,
+			Subject:  fmt.Sprintf("%s in %s (%s)", item.Name, item.Client, item.Scope),
+			Problem:  fmt.Sprintf("%s has no SKILL.md", item.Path),
+			Fix:      fmt.Sprintf("reinstall with \"%s install %s --force -c %s %s\" or remove the directory", a.binaryName, item.Name, item.Client, scopeFlag),
+		})
+	}
+	return items
+}
+
+func checkSkillRequires(report *doctor.Report, items []skill.Installed) {
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.Name] {
+			continue
+		}
+		seen[item.Name] = true
+		entry, ok, err := registryindex.FindSkill(registryindex.RecordedSource("skill", item.Name))
+		if err != nil || !ok || len(entry.Requires) == 0 {
+			continue
+		}
+		var missing []string
+		for _, req := range entry.Requires {
+			req = strings.TrimSpace(req)
+			if req == "" {
+				continue
+			}
+			if _, err := exec.LookPath(req); err != nil {
+				missing = append(missing, req)
+			}
+		}
+		if len(missing) == 0 {
+			report.Pass()
+			continue
+		}
+		report.Add(doctor.Finding{
+			Severity: doctor.SeverityWarning,
+			Subject:  item.Name,
+			Problem:  fmt.Sprintf("registry lists requirements that are not on PATH: %s", strings.Join(missing, ", ")),
+			Fix:      fmt.Sprintf("install %s", strings.Join(missing, ", ")),
+		})
+	}
+}
+
+func checkSkillStore(report *doctor.Report) {
+	registries, err := registryindex.Registries()
+	if err != nil {
+		return
+	}
+	for _, reg := range registries {
+		store, err := registryindex.StoreDir(reg.Name, "skill")
+		if err != nil {
+			continue
+		}
+		entries, err := os.ReadDir(store)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				report.Add(doctor.Finding{Severity: doctor.SeverityError, Subject: "skill cache", Problem: err.Error()})
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			registry, name := reg.Name, entry.Name()
+			path := filepath.Join(store, name)
+			if hasSkillFile(path) {
+				report.Pass()
+				continue
+			}
+			label := name
+			if registry != registryindex.DefaultRegistry {
+				label = registryindex.QualifiedName(registry, name)
+			}
+			report.Add(doctor.Finding{
+				Severity: doctor.SeverityWarning,
+				Subject:  "cached skill " + label,
+				Problem:  fmt.Sprintf("%s has no SKILL.md", path),
+				Fix:      "remove the broken cache entry so it is fetched again",
+				Repair: func() error {
+					if err := safefile.RemoveAll(path); err != nil {
+						return err
+					}
+					return registryindex.RemoveLocalRecord("skill", registry, name)
+				},
+			})
+		}
+	}
+}
+
+func hasSkillFile(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "SKILL.md"))
+	return err == nil && info.Mode().IsRegular()
+}
+
+func (a *App) printDoctorHelp() {
+	fmt.Fprintf(a.out, `Usage: %s doctor [--client|-c <list>] [--fix]
+
+What it does:
+  - Finds installed skill directories (user and project scope) without SKILL.md
+  - Warns when tools listed in a skill's registry "requires" field are not on PATH
+  - Finds skills in the local cache (~/.mcp-skill/skill and each registry's skill/) without SKILL.md
+  - Finds ~/.mcp-skill/.meta/skill records whose cached copy was deleted
+  - Warns when the registry index is missing, unreadable or older than 7 days
+  - --fix applies the safe fixes: removes broken cache entries and stale records, re-downloads the index
+  - Exits 1 when errors remain, 0 otherwise
+
+Examples:
+  %s doctor
+  %s doctor -c claude
+  %s doctor --fix
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+}
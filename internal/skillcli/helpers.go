@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/registryindex"
 )
 
 func resolveScope(scope string, global bool, local bool) (string, error) {
@@ -137,6 +139,9 @@ func splitArgs(args []string) ([]string, []string) {
 		"--tool":   true,
 		"--client": true,
 		"-c":       true,
+		"--output": true,
+		"-o":       true,
+		"--file":   true,
 	}
 
 	for i := 0; i < len(args); i++ {
@@ -156,3 +161,16 @@ func splitArgs(args []string) ([]string, []string) {
 
 	return flags, positionals
 }
+
+func startDryRun(passthroughCache bool) error {
+	var passthrough []string
+	if passthroughCache {
+		paths, err := registryindex.CachePaths()
+		if err != nil {
+			return err
+		}
+		passthrough = paths
+	}
+	plan.Start(passthrough...)
+	return nil
+}
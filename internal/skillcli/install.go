@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
+	"mcp-skill-manager/internal/registryindex"
 	"mcp-skill-manager/internal/skill"
 	"os"
 	"strings"
@@ -21,11 +23,17 @@ func (a *App) runInstall(args []string) int {
 	projectLong := fs.Bool("project", false, "install to project/local scope")
 	forceShort := fs.Bool("f", false, "overwrite existing skills")
 	forceLong := fs.Bool("force", false, "overwrite existing skills")
+	frozenFlag := fs.Bool("frozen", false, "refuse registry skills whose head differs from the lockfile")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing anything")
 	allShort := fs.Bool("a", false, "install for all clients")
 	allLong := fs.Bool("all", false, "install for all clients")
 	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,amp,kilocode,roo,goose,antigravity,copilot,clawdbot,droid,windsurf")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 	flags, positionals := splitArgs(args)
@@ -37,6 +45,25 @@ func (a *App) runInstall(args []string) int {
 		a.printInstallHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
+	promptOut := a.out
+	if format.Structured() {
+		promptOut = a.errOut
+	}
 
 	if len(positionals) == 0 {
 		fmt.Fprintln(a.errOut, "install requires a repo, path, or local skill name")
@@ -62,21 +89,32 @@ func (a *App) runInstall(args []string) int {
 	source := positionals[0]
 	cwd, _ := os.Getwd()
 	force := *forceShort || *forceLong
+	frozen := *frozenFlag
+	if *dryRun {
+		if err := startDryRun(true); err != nil {
+			fmt.Fprintf(a.errOut, "install failed: %v\n", err)
+			return 1
+		}
+		defer plan.Stop()
+	}
 
 	var records []skill.Installed
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		var installErr error
-		records, installErr = skill.Install(source, normalizedScope, cwd, tools, force)
+		records, installErr = skill.Install(source, normalizedScope, cwd, tools, skill.InstallOptions{Force: force, Frozen: frozen})
 		return installErr
 	})
 	if err != nil && !force && isAlreadyExistsError(err) {
-		if !confirmPrompt(a.out, "Skill already exists. Overwrite? Type 'yes' to continue: ") {
-			fmt.Fprintln(a.out, "canceled")
+		if !*dryRun && !confirmPrompt(promptOut, "Skill already exists. Overwrite? Type 'yes' to continue: ") {
+			fmt.Fprintln(promptOut, "canceled")
+			if format.Structured() {
+				return a.writeOutput(format, "skill.install", nil)
+			}
 			return 0
 		}
 		err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 			var installErr error
-			records, installErr = skill.Install(source, normalizedScope, cwd, tools, true)
+			records, installErr = skill.Install(source, normalizedScope, cwd, tools, skill.InstallOptions{Force: true, Frozen: frozen})
 			return installErr
 		})
 	}
@@ -84,6 +122,13 @@ func (a *App) runInstall(args []string) int {
 		fmt.Fprintf(a.errOut, "install failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+		return 0
+	}
+	if format.Structured() {
+		return a.writeOutput(format, "skill.install", installedResults(records, "installed"))
+	}
 
 	for _, record := range records {
 		fmt.Fprintf(a.out, "installed %s -> %s (%s)\n", record.Name, record.Path, record.Client)
@@ -92,7 +137,22 @@ func (a *App) runInstall(args []string) int {
 }
 
 func (a *App) printInstallHelp() {
-	fmt.Fprintf(a.out, `Usage: %s install <repo|path|name> [--global|-g] [--local|-l] [--force|-f] [--client|-c <list>] [--all|-a]
+	fmt.Fprintf(a.out, `Usage: %s install <repo|path|name> [--global|-g] [--local|-l] [--force|-f] [--frozen] [--dry-run] [--output|-o <fmt>] [--client|-c <list>] [--all|-a] [--offline] [--refresh]
+
+Lockfile:
+  Registry installs record head, updatedAt, repo and content hash in .mcp-skill.lock.json
+  (next to .mcp-skill.json, or in ~/.mcp-skill), keyed <registry>/<name> outside the default
+  registry. --frozen refuses to install skills whose registry head or content differs from the
+  lockfile, and checks a fresh download before it replaces the cached copy.
+
+Dry run:
+  --dry-run lists the skill directories that would be copied or replaced and the lockfile
+  diff. Registry skills are downloaded to a temporary directory so the plan is exact; the
+  cache and record updates are listed, not written.
+
+Offline:
+  --offline (or MCP_SKILL_OFFLINE=1) never touches the network or git. Registry skills are
+  installed from ~/.mcp-skill/skill using the cached index; repository sources are refused.
 
 Examples:
   %s install openai/skills
@@ -101,7 +161,8 @@ Examples:
   %s i https://github.com/openai/skills.git -c codex,claude
   %s install openai/skills -g -c opencode
   %s install openai/skills -g -a
-`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+  %s install react-best-practices -c claude --frozen
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
 
 func isAlreadyExistsError(err error) bool {
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"mcp-skill-manager/internal/cli"
@@ -25,6 +26,10 @@ func (a *App) runList(args []string) int {
 	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,amp,kilocode,roo,goose,antigravity,copilot,clawdbot,droid,windsurf")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -36,6 +41,12 @@ func (a *App) runList(args []string) int {
 		a.printListHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
 
 	if len(positionals) > 1 {
 		fmt.Fprintln(a.errOut, "list accepts at most one skill name")
@@ -46,8 +57,14 @@ func (a *App) runList(args []string) int {
 		skillFilter = positionals[0]
 	}
 
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
 	if *availableShort || *availableLong {
-		return a.runListAvailable(skillFilter)
+		return a.runListAvailable(skillFilter, format)
 	}
 
 	clientValue, err := resolveListClientValue(*clientFlag, *clientShort, *toolFlag)
@@ -74,6 +91,24 @@ func (a *App) runList(args []string) int {
 		return 1
 	}
 
+	if format.Structured() {
+		records := []installedRecord{}
+		for _, item := range items {
+			if !matchesSkillFilter(item.Name, skillFilter) {
+				continue
+			}
+			record := installedRecord{Installed: item}
+			record.Version, _ = readSkillVersion(item.Path)
+			if meta, err := loadSkillMeta(item.Path); err == nil {
+				record.Description = meta.Description
+			} else {
+				record.Description, _ = readSkillDescription(item.Path)
+			}
+			records = append(records, record)
+		}
+		return a.writeOutput(format, "skill.installed", records)
+	}
+
 	if len(items) == 0 {
 		fmt.Fprintln(a.out, "no skills installed")
 		return 0
@@ -134,14 +169,20 @@ func (a *App) runList(args []string) int {
 	return 0
 }
 
-func (a *App) runListAvailable(filter string) int {
+func (a *App) runListAvailable(filter string, format cli.OutputFormat) int {
 	var outputErr error
 	type row struct {
 		name        string
+		registry    string
 		updatedAt   string
+		offline     string
 		description string
 	}
 	var rows []row
+	matched := []registryindex.SkillEntry{}
+	showRegistry := false
+	offline := registryindex.Offline()
+	unavailable := 0
 	err := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		if err := registryindex.EnsureIndexes(); err != nil {
 			outputErr = err
@@ -152,14 +193,28 @@ func (a *App) runListAvailable(filter string) int {
 			outputErr = err
 			return err
 		}
+		registries, err := registryindex.Registries()
+		if err != nil {
+			outputErr = err
+			return err
+		}
+		showRegistry = len(registries) > 1
 
 		for _, entry := range index.Skills {
 			if filter != "" && !matchesSkillFilter(entry.Name, filter) {
 				continue
 			}
+			matched = append(matched, entry)
+			status := "available"
+			if !registryindex.SkillAvailableOffline(entry) {
+				status = "unavailable"
+				unavailable++
+			}
 			rows = append(rows, row{
 				name:        entry.Name,
+				registry:    entry.Registry,
 				updatedAt:   entry.UpdatedAt,
+				offline:     status,
 				description: truncateDescription(entry.Description, 80),
 			})
 		}
@@ -169,25 +224,50 @@ func (a *App) runListAvailable(filter string) int {
 		fmt.Fprintf(a.errOut, "list failed: %v\n", outputErr)
 		return 1
 	}
+	if format.Structured() {
+		return a.writeOutput(format, "skill.registry", matched)
+	}
 
 	if len(rows) == 0 {
 		fmt.Fprintln(a.out, "no matching skills found")
 		return 0
 	}
 	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
-	fmt.Fprintln(writer, "SKILL\tUPDATED\tDESCRIPTION")
+	header := []string{"SKILL"}
+	if showRegistry {
+		header = append(header, "REGISTRY")
+	}
+	header = append(header, "UPDATED")
+	if offline {
+		header = append(header, "OFFLINE")
+	}
+	fmt.Fprintln(writer, strings.Join(append(header, "DESCRIPTION"), "\t"))
 	for _, item := range rows {
-		fmt.Fprintf(writer, "%s\t%s\t%s\n", item.name, item.updatedAt, item.description)
+		columns := []string{item.name}
+		if showRegistry {
+			columns = append(columns, item.registry)
+		}
+		columns = append(columns, item.updatedAt)
+		if offline {
+			columns = append(columns, item.offline)
+		}
+		fmt.Fprintln(writer, strings.Join(append(columns, item.description), "\t"))
 	}
 	if err := writer.Flush(); err != nil {
 		fmt.Fprintf(a.errOut, "list failed: %v\n", err)
 		return 1
 	}
+	if offline && unavailable > 0 {
+		fmt.Fprintf(a.errOut, "offline: %d of %d skills need the network (they are not in ~/.mcp-skill/skill)\n", unavailable, len(rows))
+	}
 	return 0
 }
 
 func (a *App) printListHelp() {
-	fmt.Fprintf(a.out, `Usage: %s list [skill] [--global|-g] [--local|-l] [--client|-c <list>] [--available|-a]
+	fmt.Fprintf(a.out, `Usage: %s list [skill] [--global|-g] [--local|-l] [--client|-c <list>] [--available|-a] [--output|-o <text|json|yaml>] [--offline] [--refresh]
+
+With --available --offline (or MCP_SKILL_OFFLINE=1) the cached index is listed with an OFFLINE
+column telling which skills are in ~/.mcp-skill/skill and can be installed without the network.
 
 Examples:
   %s list
@@ -195,5 +275,6 @@ Examples:
   %s list my-skill -l
   %s list my-skill -g -c opencode
   %s list -g
-`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
+  %s list -g -o json
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
@@ -2,10 +2,6 @@ package skillcli
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -44,48 +40,44 @@ func readSkillDescription(skillPath string) (string, error) {
 	return description, err
 }
 
-type remoteMetaError struct {
-	StatusCode int
-	Body       string
-}
-
-func (e remoteMetaError) Error() string {
-	if e.Body == "" {
-		return fmt.Sprintf("remote fetch failed: status %d", e.StatusCode)
+func fetchRemoteSkillMeta(entry registryindex.SkillEntry) (SkillMeta, error) {
+	file := "skill/" + entry.Name + "/skill.meta.json"
+	meta, err := decodeSkillMeta(registryindex.FetchCached(entry.Registry, file, false))
+	if err != nil {
+		return SkillMeta{}, err
 	}
-	return fmt.Sprintf("remote fetch failed: status %d (%s)", e.StatusCode, e.Body)
-}
-
-func isRemoteNotFound(err error) bool {
-	var remoteErr remoteMetaError
-	if !errors.As(err, &remoteErr) {
-		return false
+	if entry.Head != "" && meta.Head != "" && meta.Head != entry.Head {
+		return decodeSkillMeta(registryindex.FetchCached(entry.Registry, file, true))
 	}
-	return remoteErr.StatusCode == http.StatusNotFound
+	return meta, nil
 }
 
-func fetchRemoteSkillMeta(name string) (SkillMeta, error) {
-	base, err := registryindex.RawBaseURL()
+func decodeSkillMeta(data []byte, err error) (SkillMeta, error) {
 	if err != nil {
 		return SkillMeta{}, err
 	}
-	url := fmt.Sprintf("%sskill/%s/skill.meta.json", base, name)
-	resp, err := http.Get(url)
-	if err != nil {
-		return SkillMeta{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 8*1024))
-		return SkillMeta{}, remoteMetaError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
-	}
 	var meta SkillMeta
-	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+	if err := json.Unmarshal(data, &meta); err != nil {
 		return SkillMeta{}, err
 	}
 	return meta, nil
 }
 
+func cachedSkillMeta(entry registryindex.SkillEntry) SkillMeta {
+	if path, err := registryindex.SkillPathInStore(entry.Registry, entry.Name); err == nil {
+		if meta, err := loadSkillMeta(path); err == nil {
+			return meta
+		}
+	}
+	return SkillMeta{
+		Name:        entry.Name,
+		Description: entry.Description,
+		Version:     entry.Version,
+		Head:        entry.Head,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+}
+
 func readFrontmatterFromSkill(path string) (string, string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -0,0 +1,317 @@
+package skillcli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-skill-manager/internal/cli"
+	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/manifest"
+	"mcp-skill-manager/internal/registryindex"
+	"mcp-skill-manager/internal/skill"
+)
+
+type syncTarget struct {
+	skill       manifest.Skill
+	tools       []installer.Tool
+	scope       string
+	source      string
+	name        string
+	entry       registryindex.SkillEntry
+	registry    bool
+	registryErr error
+	err         error
+}
+
+type syncResult struct {
+	name    string
+	client  installer.Tool
+	scope   string
+	message string
+	err     error
+}
+
+func (a *App) runSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	fileFlag := fs.String("file", "", "manifest path (default: nearest "+manifest.DefaultFile+")")
+	pruneFlag := fs.Bool("prune", false, "remove skills not listed in the manifest")
+	forceShort := fs.Bool("f", false, "reinstall every skill even if up to date")
+	forceLong := fs.Bool("force", false, "reinstall every skill even if up to date")
+	frozenFlag := fs.Bool("frozen", false, "refuse registry skills whose head differs from the lockfile")
+	helpShort := fs.Bool("h", false, "show help")
+	helpLong := fs.Bool("help", false, "show help")
+
+	flags, positionals := splitArgs(args)
+	if err := fs.Parse(flags); err != nil {
+		return 2
+	}
+	if *helpShort || *helpLong {
+		a.printSyncHelp()
+		return 0
+	}
+	if len(positionals) > 0 {
+		fmt.Fprintln(a.errOut, "sync does not accept positional arguments")
+		return 2
+	}
+
+	cwd, _ := os.Getwd()
+	path := strings.TrimSpace(*fileFlag)
+	if path == "" {
+		found, err := manifest.Find(cwd)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+			return 1
+		}
+		path = found
+	}
+	m, err := manifest.Load(path)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+		return 1
+	}
+	if len(m.Skills) == 0 {
+		fmt.Fprintln(a.out, "no skills in manifest")
+		return 0
+	}
+
+	registryErr := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+		return registryindex.EnsureIndexes()
+	})
+	if registryErr != nil {
+		if *frozenFlag {
+			fmt.Fprintf(a.errOut, "sync failed: %v\n", registryErr)
+			return 1
+		}
+		fmt.Fprintf(a.errOut, "warning: registry index unavailable, only local skills can be synced: %v\n", registryErr)
+	}
+
+	root := m.Dir()
+
+	var targets []syncTarget
+	for _, item := range m.Skills {
+		tools, scope, err := m.Targets(item.Clients, item.Scope)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "invalid manifest: %s: %v\n", item.Name, err)
+			return 2
+		}
+		targets = append(targets, resolveSyncTarget(syncTarget{
+			skill:  item,
+			tools:  tools,
+			scope:  scope,
+			source: m.ResolvePath(item.SourceName()),
+		}, registryErr))
+	}
+
+	installed := map[string]skill.Installed{}
+	for _, target := range targets {
+		items, err := skill.List([]string{target.scope}, root, target.tools)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+			return 1
+		}
+		for _, item := range items {
+			installed[syncKey(item.Client, item.Scope, item.Name)] = item
+		}
+	}
+
+	opts := skill.InstallOptions{Force: *forceShort || *forceLong, Frozen: *frozenFlag}
+	var results []syncResult
+	for _, target := range targets {
+		results = append(results, a.syncSkill(target, installed, root, opts)...)
+	}
+
+	if *pruneFlag {
+		extras := collectSyncExtras(targets, installed)
+		if len(extras) > 0 && confirmRemoval(a.out, extras) {
+			for _, item := range extras {
+				_, err := skill.Uninstall(item.Name, item.Scope, root, []installer.Tool{item.Client}, true)
+				if err != nil {
+					results = append(results, syncResult{name: item.Name, client: item.Client, scope: item.Scope, err: err})
+					continue
+				}
+				results = append(results, syncResult{name: item.Name, client: item.Client, scope: item.Scope, message: "removed"})
+			}
+		}
+	}
+
+	rows := make([]cli.BatchRow, 0, len(results))
+	for _, res := range results {
+		row := cli.BatchRow{Name: res.name, Client: string(res.client), Scope: res.scope, Result: res.message, Outcome: cli.OutcomeChanged}
+		switch {
+		case res.err != nil:
+			fmt.Fprintf(a.errOut, "sync failed for %s (%s/%s): %v\n", res.name, res.client, res.scope, res.err)
+			row.Result = "failed"
+			row.Outcome = cli.OutcomeFailed
+		case res.message == "up to date":
+			row.Outcome = cli.OutcomeUnchanged
+		}
+		rows = append(rows, row)
+	}
+	if err := cli.PrintBatchTable(a.out, rows); err != nil {
+		fmt.Fprintf(a.errOut, "sync failed: %v\n", err)
+		return 1
+	}
+	if code := cli.SummarizeBatch(rows).ExitCode(); code == cli.ExitFailure || code == cli.ExitPartialFailure {
+		return code
+	}
+	return 0
+}
+
+func (a *App) syncSkill(target syncTarget, installed map[string]skill.Installed, cwd string, opts skill.InstallOptions) []syncResult {
+	name := strings.TrimSpace(target.skill.Name)
+	var missing []installer.Tool
+	var results []syncResult
+	var stale []installer.Tool
+	if target.err != nil {
+		for _, tool := range target.tools {
+			results = append(results, syncResult{name: name, client: tool, scope: target.scope, err: target.err})
+		}
+		return results
+	}
+	for _, tool := range target.tools {
+		item, ok := installed[syncKey(tool, target.scope, target.name)]
+		if !ok {
+			missing = append(missing, tool)
+			continue
+		}
+		if opts.Force {
+			stale = append(stale, tool)
+			continue
+		}
+		if !target.registry {
+			results = append(results, syncResult{name: name, client: tool, scope: target.scope, message: "up to date"})
+			continue
+		}
+		drifted, err := registrySkillDrifted(target.entry, item.Path)
+		if err != nil {
+			results = append(results, syncResult{name: name, client: tool, scope: target.scope, err: err})
+			continue
+		}
+		if !drifted {
+			results = append(results, syncResult{name: name, client: tool, scope: target.scope, message: "up to date"})
+			continue
+		}
+		stale = append(stale, tool)
+	}
+
+	install := func(tools []installer.Tool, message string) {
+		if len(tools) == 0 {
+			return
+		}
+		err := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+			_, err := skill.Install(target.source, target.scope, cwd, tools, skill.InstallOptions{Force: true, Frozen: opts.Frozen})
+			return err
+		})
+		if err != nil && target.registryErr != nil && !strings.Contains(err.Error(), target.registryErr.Error()) {
+			err = fmt.Errorf("%w (registry index unavailable: %v)", err, target.registryErr)
+		}
+		for _, tool := range tools {
+			if err != nil {
+				results = append(results, syncResult{name: name, client: tool, scope: target.scope, err: err})
+				continue
+			}
+			results = append(results, syncResult{name: name, client: tool, scope: target.scope, message: message})
+		}
+	}
+	install(missing, "installed")
+	install(stale, "updated")
+	return results
+}
+
+func resolveSyncTarget(target syncTarget, registryErr error) syncTarget {
+	target.name = strings.TrimSpace(target.skill.Name)
+	if _, err := os.Stat(target.source); err == nil {
+		if hasSkillFile(target.source) {
+			target.name = filepath.Base(filepath.Clean(target.source))
+		}
+		return target
+	}
+	if registryErr != nil {
+		target.registryErr = registryErr
+		return target
+	}
+	entry, ok, err := registryindex.FindSkill(target.source)
+	if err != nil {
+		target.err = err
+		return target
+	}
+	if ok {
+		target.entry, target.registry, target.name = entry, true, entry.Name
+	}
+	return target
+}
+
+func registrySkillDrifted(entry registryindex.SkillEntry, installedPath string) (bool, error) {
+	var drifted bool
+	err := withSyncedSkill(entry, func(cachedPath string) error {
+		var err error
+		drifted, _, _, err = needsSkillUpdate(installedPath, cachedPath)
+		return err
+	})
+	return drifted, err
+}
+
+func collectSyncExtras(targets []syncTarget, installed map[string]skill.Installed) []skill.Installed {
+	managed := map[string]bool{}
+	declared := map[string]bool{}
+	for _, target := range targets {
+		for _, tool := range target.tools {
+			managed[string(tool)+":"+target.scope] = true
+			declared[syncKey(tool, target.scope, target.name)] = true
+		}
+	}
+
+	var extras []skill.Installed
+	for key, item := range installed {
+		if declared[key] || !managed[string(item.Client)+":"+item.Scope] {
+			continue
+		}
+		extras = append(extras, item)
+	}
+	sort.Slice(extras, func(i, j int) bool {
+		if extras[i].Client != extras[j].Client {
+			return extras[i].Client < extras[j].Client
+		}
+		if extras[i].Scope != extras[j].Scope {
+			return extras[i].Scope < extras[j].Scope
+		}
+		return extras[i].Name < extras[j].Name
+	})
+	return extras
+}
+
+func syncKey(tool installer.Tool, scope, name string) string {
+	return string(tool) + ":" + scope + ":" + name
+}
+
+func (a *App) printSyncHelp() {
+	fmt.Fprintf(a.out, `Usage: %s sync [--file <path>] [--prune] [--force|-f] [--frozen]
+
+What it does:
+  - Reads the "skills" section of %s (searched from the current directory upwards)
+  - Project-scope entries are installed, listed and pruned in the directory holding the manifest
+  - Installs skills missing from the listed clients/scopes
+  - Reinstalls registry skills whose content changed
+  - With --prune: removes skills not listed for the clients/scopes the manifest manages
+  - With --frozen: refuses registry skills whose head differs from .mcp-skill.lock.json
+  - Exits 3 when some entries failed and 1 when all of them failed
+
+Manifest example:
+  {
+    "clients": ["claude", "opencode"],
+    "skills": [
+      { "name": "react-best-practices" },
+      { "name": "work-session", "source": "./skills/work-session", "scope": "user" }
+    ]
+  }
+
+Examples:
+  %s sync
+  %s sync --file ./.mcp-skill.json --prune
+`, a.binaryName, manifest.DefaultFile, a.binaryName, a.binaryName)
+}
@@ -8,6 +8,7 @@ import (
 
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/skill"
 )
 
@@ -27,6 +28,9 @@ func (a *App) runUninstall(args []string) int {
 	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,amp,kilocode,roo,goose,antigravity,copilot,clawdbot,droid,windsurf")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without removing anything")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -39,6 +43,19 @@ func (a *App) runUninstall(args []string) int {
 		a.printUninstallHelp()
 		return 0
 	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
+	promptOut := a.out
+	if format.Structured() {
+		promptOut = a.errOut
+	}
 
 	allRequested := *allShort || *allLong
 	clientValue, err := resolveClientValue(*clientFlag, *clientShort, *toolFlag, allRequested)
@@ -67,11 +84,17 @@ func (a *App) runUninstall(args []string) int {
 			return 1
 		}
 		if len(targets) == 0 {
+			if format.Structured() {
+				return a.writeOutput(format, "skill.uninstall", nil)
+			}
 			fmt.Fprintln(a.out, "no matching skills found")
 			return 0
 		}
-		if !confirmRemoval(a.out, targets) {
-			fmt.Fprintln(a.out, "canceled")
+		if !*dryRun && !confirmRemoval(promptOut, targets) {
+			fmt.Fprintln(promptOut, "canceled")
+			if format.Structured() {
+				return a.writeOutput(format, "skill.uninstall", nil)
+			}
 			return 0
 		}
 	}
@@ -79,6 +102,10 @@ func (a *App) runUninstall(args []string) int {
 		fmt.Fprintln(a.errOut, "uninstall requires a skill name (or use -a)")
 		return 2
 	}
+	if *dryRun {
+		plan.Start()
+		defer plan.Stop()
+	}
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		var uninstallErr error
 		if len(positionals) == 0 {
@@ -93,6 +120,13 @@ func (a *App) runUninstall(args []string) int {
 		fmt.Fprintf(a.errOut, "uninstall failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+		return 0
+	}
+	if format.Structured() {
+		return a.writeOutput(format, "skill.uninstall", installedResults(records, "removed"))
+	}
 
 	for _, record := range records {
 		fmt.Fprintf(a.out, "removed %s from %s (%s)\n", record.Name, record.Path, record.Client)
@@ -101,7 +135,7 @@ func (a *App) runUninstall(args []string) int {
 }
 
 func (a *App) printUninstallHelp() {
-	fmt.Fprintf(a.out, `Usage: %s uninstall [name] [--global|-g] [--local|-l] [--force|-f] [--client|-c <list>] [--all|-a]
+	fmt.Fprintf(a.out, `Usage: %s uninstall [name] [--global|-g] [--local|-l] [--force|-f] [--dry-run] [--output|-o <fmt>] [--client|-c <list>] [--all|-a]
 
 Examples:
   %s uninstall my-skill -l -c opencode
@@ -7,6 +7,7 @@ import (
 
 	"mcp-skill-manager/internal/cli"
 	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/plan"
 	"mcp-skill-manager/internal/registryindex"
 	"mcp-skill-manager/internal/skill"
 )
@@ -22,6 +23,11 @@ func (a *App) runUpdate(args []string) int {
 	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,amp,kilocode,roo,goose,antigravity,copilot,clawdbot,droid,windsurf")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing anything")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -33,6 +39,21 @@ func (a *App) runUpdate(args []string) int {
 		a.printUpdateHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+	if format.Structured() && *dryRun {
+		fmt.Fprintln(a.errOut, "--dry-run cannot be combined with --output")
+		return 2
+	}
 	if len(positionals) > 1 {
 		fmt.Fprintln(a.errOut, "update accepts at most one skill name")
 		return 2
@@ -60,8 +81,12 @@ func (a *App) runUpdate(args []string) int {
 		return 1
 	}
 	if len(items) == 0 {
-		fmt.Fprintln(a.out, "no skills installed")
-		return 0
+		if format.Structured() {
+			a.writeOutput(format, "skill.update", nil)
+		} else {
+			fmt.Fprintln(a.out, "no skills installed")
+		}
+		return cli.ExitNothingToDo
 	}
 
 	var targets []skill.Installed
@@ -72,8 +97,12 @@ func (a *App) runUpdate(args []string) int {
 		targets = append(targets, item)
 	}
 	if len(targets) == 0 {
-		fmt.Fprintln(a.out, "no matching skills found")
-		return 0
+		if format.Structured() {
+			a.writeOutput(format, "skill.update", nil)
+		} else {
+			fmt.Fprintln(a.out, "no matching skills found")
+		}
+		return cli.ExitNothingToDo
 	}
 
 	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
@@ -83,37 +112,42 @@ func (a *App) runUpdate(args []string) int {
 		fmt.Fprintf(a.errOut, "update failed: %v\n", err)
 		return 1
 	}
+	if *dryRun {
+		if err := startDryRun(true); err != nil {
+			fmt.Fprintf(a.errOut, "update failed: %v\n", err)
+			return 1
+		}
+		defer plan.Stop()
+	}
 
 	type result struct {
 		item    skill.Installed
 		message string
+		outcome cli.Outcome
 		err     error
 	}
 	var results []result
 	for _, item := range targets {
-		entry, ok, err := registryindex.FindSkill(item.Name)
+		entry, ok, err := registryindex.FindSkill(registryindex.RecordedSource("skill", item.Name))
 		if err != nil {
-			results = append(results, result{item: item, err: err})
+			results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
 			continue
 		}
 		if !ok {
-			results = append(results, result{item: item, message: "not in registry"})
+			results = append(results, result{item: item, message: "not in registry", outcome: cli.OutcomeUnchanged})
 			continue
 		}
-		remoteMeta, remoteErr := fetchRemoteSkillMeta(entry.Name)
+		remoteMeta, remoteErr := fetchRemoteSkillMeta(entry)
 		if remoteErr != nil {
-			localPath, err := localStoreSkillPath(item.Name)
-			if err != nil {
-				results = append(results, result{item: item, err: err})
-				continue
-			}
-			if err := registryindex.SyncSkill(entry); err != nil {
-				results = append(results, result{item: item, err: err})
-				continue
-			}
-			needsUpdate, installedVersion, cachedVersion, err := needsSkillUpdate(item.Path, localPath)
+			var needsUpdate bool
+			var installedVersion, cachedVersion string
+			err := withSyncedSkill(entry, func(cachedPath string) error {
+				var err error
+				needsUpdate, installedVersion, cachedVersion, err = needsSkillUpdate(item.Path, cachedPath)
+				return err
+			})
 			if err != nil {
-				results = append(results, result{item: item, err: err})
+				results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
 				continue
 			}
 			if !needsUpdate {
@@ -121,20 +155,17 @@ func (a *App) runUpdate(args []string) int {
 				if installedVersion != "" {
 					label = fmt.Sprintf("already latest (%s)", installedVersion)
 				}
-				results = append(results, result{item: item, message: label})
+				results = append(results, result{item: item, message: label, outcome: cli.OutcomeUnchanged})
 				continue
 			}
-			records, err := skill.Install(entry.Name, item.Scope, cwd, []installer.Tool{item.Client}, true)
+			records, err := skill.Install(registryindex.QualifiedName(entry.Registry, entry.Name), item.Scope, cwd, []installer.Tool{item.Client}, skill.InstallOptions{Force: true})
 			if err != nil {
-				results = append(results, result{item: item, err: err})
+				results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
 				continue
 			}
 			_ = records
-			msg := "updated"
-			if cachedVersion != "" {
-				msg = fmt.Sprintf("updated to %s", cachedVersion)
-			}
-			results = append(results, result{item: item, message: msg})
+			msg := updatedMessage(cachedVersion, *dryRun)
+			results = append(results, result{item: item, message: msg, outcome: updatedOutcome(*dryRun)})
 			continue
 		}
 
@@ -145,7 +176,7 @@ func (a *App) runUpdate(args []string) int {
 		}
 		if remoteMeta.Version != "" && installedVersion != "" && remoteMeta.Version == installedVersion {
 			label := fmt.Sprintf("already latest (%s)", installedVersion)
-			results = append(results, result{item: item, message: label})
+			results = append(results, result{item: item, message: label, outcome: cli.OutcomeUnchanged})
 			continue
 		}
 		if remoteMeta.Head != "" && installedMeta.Head != "" && remoteMeta.Head == installedMeta.Head {
@@ -153,42 +184,102 @@ func (a *App) runUpdate(args []string) int {
 			if installedVersion != "" {
 				label = fmt.Sprintf("already latest (%s)", installedVersion)
 			}
-			results = append(results, result{item: item, message: label})
+			results = append(results, result{item: item, message: label, outcome: cli.OutcomeUnchanged})
 			continue
 		}
 
 		if err := registryindex.SyncSkill(entry); err != nil {
-			results = append(results, result{item: item, err: err})
+			results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
 			continue
 		}
-		records, err := skill.Install(entry.Name, item.Scope, cwd, []installer.Tool{item.Client}, true)
+		records, err := skill.Install(registryindex.QualifiedName(entry.Registry, entry.Name), item.Scope, cwd, []installer.Tool{item.Client}, skill.InstallOptions{Force: true})
 		if err != nil {
-			results = append(results, result{item: item, err: err})
+			results = append(results, result{item: item, message: failedMessage(err), outcome: cli.OutcomeFailed, err: err})
 			continue
 		}
 		_ = records
-		msg := "updated"
-		if remoteMeta.Version != "" {
-			msg = fmt.Sprintf("updated to %s", remoteMeta.Version)
-		}
-		results = append(results, result{item: item, message: msg})
+		msg := updatedMessage(remoteMeta.Version, *dryRun)
+		results = append(results, result{item: item, message: msg, outcome: updatedOutcome(*dryRun)})
 	}
 
+	rows := make([]cli.BatchRow, 0, len(results))
+	for _, res := range results {
+		rows = append(rows, cli.BatchRow{
+			Name:    res.item.Name,
+			Client:  string(res.item.Client),
+			Scope:   res.item.Scope,
+			Result:  res.message,
+			Outcome: res.outcome,
+		})
+	}
+	code := cli.SummarizeBatch(rows).ExitCode()
+
+	if format.Structured() {
+		records := make([]resultRecord, 0, len(results))
+		for _, res := range results {
+			record := resultRecord{Installed: res.item, Status: res.message}
+			if res.err != nil {
+				record.Error = res.err.Error()
+			}
+			records = append(records, record)
+		}
+		if rc := a.writeOutput(format, "skill.update", records); rc != 0 {
+			return rc
+		}
+		return code
+	}
 	for _, res := range results {
 		if res.err != nil {
 			fmt.Fprintf(a.errOut, "update failed for %s (%s/%s): %v\n", res.item.Name, res.item.Client, res.item.Scope, res.err)
-			continue
 		}
-		fmt.Fprintf(a.out, "%s (%s/%s): %s\n", res.item.Name, res.item.Client, res.item.Scope, res.message)
 	}
-	return 0
+	if err := cli.PrintBatchTable(a.out, rows); err != nil {
+		fmt.Fprintf(a.errOut, "update failed: %v\n", err)
+		return 1
+	}
+	if *dryRun {
+		cli.PrintPlan(a.out, plan.Stop())
+	}
+	return code
+}
+
+func updatedOutcome(dryRun bool) cli.Outcome {
+	if dryRun {
+		return cli.OutcomePending
+	}
+	return cli.OutcomeChanged
+}
+
+func updatedMessage(version string, dryRun bool) string {
+	msg := "updated"
+	if dryRun {
+		msg = "would update"
+	}
+	if version != "" {
+		msg = fmt.Sprintf("%s to %s", msg, version)
+	}
+	return msg
 }
 
 func (a *App) printUpdateHelp() {
-	fmt.Fprintf(a.out, `Usage: %s update [name] [--global|-g] [--local|-l] [--client|-c <list>]
+	fmt.Fprintf(a.out, `Usage: %s update [name] [--global|-g] [--local|-l] [--dry-run] [--output|-o <fmt>] [--client|-c <list>] [--offline] [--refresh]
+
+With --offline (or MCP_SKILL_OFFLINE=1) skills are brought up to date with ~/.mcp-skill/skill
+without the network or git; skills missing from it are reported as "unavailable offline".
+Registry indexes and each skill.meta.json are cached and revalidated with conditional requests
+once MCP_REGISTRY_TTL (default 5m) has passed; --refresh revalidates them now.
+
+Exit codes:
+  0  at least one skill updated, none failed
+  1  every skill failed (or the command could not run)
+  2  invalid usage
+  3  partial failure: some skills failed, others succeeded or were already latest
+  4  nothing to do: no matching skills, or all already latest
+  5  update available (--dry-run found skills that would be updated)
 
 Examples:
   %s update
   %s update work-session -l -c claude
-`, a.binaryName, a.binaryName, a.binaryName)
+  %s update --dry-run || [ $? -ne 5 ]
+`, a.binaryName, a.binaryName, a.binaryName, a.binaryName)
 }
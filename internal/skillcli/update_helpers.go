@@ -5,15 +5,19 @@ import (
 	"os"
 	"path/filepath"
 
-	"mcp-skill-manager/internal/installer"
+	"mcp-skill-manager/internal/registryindex"
 )
 
-func localStoreSkillPath(name string) (string, error) {
-	root, err := installer.LocalSkillStore()
+func withSyncedSkill(entry registryindex.SkillEntry, fn func(cachedPath string) error) error {
+	staged, err := registryindex.StageSkill(entry)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return filepath.Join(root, name), nil
+	defer staged.Cleanup()
+	if err := staged.Commit(); err != nil {
+		return err
+	}
+	return fn(staged.Path)
 }
 
 func needsSkillUpdate(installedPath, cachedPath string) (bool, string, string, error) {
@@ -43,3 +47,10 @@ func needsSkillUpdate(installedPath, cachedPath string) (bool, string, string, e
 	}
 	return true, installedVersion, cachedVersion, nil
 }
+
+func failedMessage(err error) string {
+	if registryindex.IsOffline(err) {
+		return "unavailable offline"
+	}
+	return "failed"
+}
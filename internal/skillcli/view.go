@@ -23,6 +23,10 @@ func (a *App) runView(args []string) int {
 	clientFlag := fs.String("client", "", "comma-separated clients: claude,codex,gemini,opencode,cursor,amp,kilocode,roo,goose,antigravity,copilot,clawdbot,droid,windsurf (installed only)")
 	clientShort := fs.String("c", "", "alias for --client")
 	toolFlag := fs.String("tool", "", "deprecated: use --client")
+	outputLong := fs.String("output", "", "output format: text, json or yaml")
+	outputShort := fs.String("o", "", "alias for --output")
+	offlineFlag := fs.Bool("offline", false, "use only the local cache; never touch the network or git")
+	refreshFlag := fs.Bool("refresh", false, "revalidate cached registry indexes and metadata now instead of after MCP_REGISTRY_TTL")
 	helpShort := fs.Bool("h", false, "show help")
 	helpLong := fs.Bool("help", false, "show help")
 
@@ -34,6 +38,12 @@ func (a *App) runView(args []string) int {
 		a.printViewHelp()
 		return 0
 	}
+	if *offlineFlag {
+		registryindex.SetOffline(true)
+	}
+	if *refreshFlag {
+		registryindex.SetRefresh(true)
+	}
 	if len(positionals) == 0 {
 		fmt.Fprintln(a.errOut, "view requires a skill name")
 		return 2
@@ -43,6 +53,12 @@ func (a *App) runView(args []string) int {
 		return 2
 	}
 
+	format, err := resolveOutputFormat(*outputLong, *outputShort)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "invalid output format: %v\n", err)
+		return 2
+	}
+
 	name := positionals[0]
 	if *installedFlag {
 		clientValue, err := resolveListClientValue(*clientFlag, *clientShort, *toolFlag)
@@ -74,6 +90,26 @@ func (a *App) runView(args []string) int {
 				matches = append(matches, item)
 			}
 		}
+		if format.Structured() {
+			records := []installedRecord{}
+			for _, item := range matches {
+				record := installedRecord{Installed: item}
+				meta, err := loadSkillMeta(item.Path)
+				if err != nil && !os.IsNotExist(err) {
+					fmt.Fprintf(a.errOut, "view failed: %v\n", err)
+					return 1
+				}
+				if err == nil {
+					record.Meta = &meta
+					record.Description = meta.Description
+				} else {
+					record.Description, _ = readSkillDescription(item.Path)
+				}
+				record.Version, _ = readSkillVersion(item.Path)
+				records = append(records, record)
+			}
+			return a.writeOutput(format, "skill.installed", records)
+		}
 		if len(matches) == 0 {
 			fmt.Fprintln(a.out, "no matching skills found")
 			return 0
@@ -94,7 +130,7 @@ func (a *App) runView(args []string) int {
 		return 0
 	}
 
-	err := cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
+	err = cli.RunWithSpinner(a.errOut, "", cli.DefaultTips(), cli.DefaultSpinnerDelay, func() error {
 		return registryindex.EnsureIndexes()
 	})
 	if err != nil {
@@ -107,24 +143,44 @@ func (a *App) runView(args []string) int {
 		return 1
 	}
 	if !ok {
+		if format.Structured() {
+			return a.writeOutput(format, "skill.meta", nil)
+		}
 		fmt.Fprintln(a.out, "skill not found in registry")
 		return 0
 	}
-	meta, err := fetchRemoteSkillMeta(entry.Name)
+	meta, err := fetchRemoteSkillMeta(entry)
+	if registryindex.IsOffline(err) {
+		meta, err = cachedSkillMeta(entry), nil
+		if !registryindex.SkillAvailableOffline(entry) {
+			fmt.Fprintf(a.errOut, "offline: %s needs the network to install (it is not in ~/.mcp-skill/skill)\n", entry.Name)
+		}
+	}
 	if err != nil {
-		if isRemoteNotFound(err) {
+		if registryindex.IsNotFound(err) {
+			if format.Structured() {
+				return a.writeOutput(format, "skill.meta", nil)
+			}
 			fmt.Fprintln(a.out, "skill not found in registry")
 			return 0
 		}
 		fmt.Fprintf(a.errOut, "view failed: %v\n", err)
 		return 1
 	}
+	if format.Structured() {
+		if meta.Name == "" {
+			meta.Name = entry.Name
+		}
+		return a.writeOutput(format, "skill.meta", []SkillMeta{meta})
+	}
 	printSkillMeta(a.out, entry.Name, meta, meta.Version)
 	return 0
 }
 
 func (a *App) printViewHelp() {
-	fmt.Fprintf(a.out, `Usage: %s view <name> [--installed] [--global|-g] [--local|-l] [--client|-c <list>]
+	fmt.Fprintf(a.out, `Usage: %s view <name> [--installed] [--global|-g] [--local|-l] [--client|-c <list>] [--output|-o <text|json|yaml>] [--offline] [--refresh]
+
+With --offline (or MCP_SKILL_OFFLINE=1) the metadata comes from ~/.mcp-skill/skill or the cached index.
 
 Examples:
   %s view work-session